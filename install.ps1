@@ -8,7 +8,7 @@ $ErrorActionPreference = "Stop"
 # Configuration
 $RepoOwner = "zxj777"
 $RepoName = "claude-helper"
-$BinaryName = "claude-helper.exe"
+$BinaryName = "cchp.exe"
 
 # Detect architecture
 $Arch = if ([Environment]::Is64BitOperatingSystem) { "amd64" } else { "386" }
@@ -25,7 +25,7 @@ if (-not $Tag) {
 Write-Host "Latest version: $Tag"
 
 # Construct download URL
-$BinaryFile = "claude-helper-windows-$Arch.exe"
+$BinaryFile = "cchp-windows-$Arch.exe"
 $DownloadUrl = "https://github.com/$RepoOwner/$RepoName/releases/download/$Tag/$BinaryFile"
 
 Write-Host "Downloading $DownloadUrl..."
@@ -54,9 +54,9 @@ Write-Host ""
 Write-Host "The binary has been installed to: $OutputPath"
 Write-Host "Added $InstallDir to user PATH"
 Write-Host ""
-Write-Host "IMPORTANT: To use 'claude-helper' command:"
+Write-Host "IMPORTANT: To use 'cchp' command:"
 Write-Host "1. Restart your terminal/IDE completely (close and reopen)"
 Write-Host "2. Or run the full path: $OutputPath"
-Write-Host "3. Or in current session: `$env:PATH += ';$InstallDir'; claude-helper --help"
+Write-Host "3. Or in current session: `$env:PATH += ';$InstallDir'; cchp --help"
 Write-Host ""
 Write-Host "If you are using an IDE (like VS Code), the IDE PowerShell may need to be restarted separately."
\ No newline at end of file
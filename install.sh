@@ -5,7 +5,7 @@ set -e
 # Configuration
 REPO_OWNER="zxj777"  # Replace with your GitHub username
 REPO_NAME="claude-helper"
-BINARY_NAME="claude-helper"
+BINARY_NAME="cchp"
 INSTALL_DIR="/usr/local/bin"
 
 # Detect OS and Architecture
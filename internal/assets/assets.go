@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+
+	"github.com/zxj777/claude-helper/internal/config"
 )
 
 //go:embed templates/*
@@ -15,199 +17,100 @@ var templatesFS embed.FS
 //go:embed sounds/*
 var soundsFS embed.FS
 
-// GetTemplatesDir returns the path to the templates directory
-// If running from source (development), it uses the local internal/assets/templates
-// If running from built binary, it extracts embedded files to a temp location
-func GetTemplatesDir() (string, error) {
-	// First try to find local templates directory (for development)
-	if wd, err := os.Getwd(); err == nil {
-		localTemplatesDir := filepath.Join(wd, "internal", "assets", "templates")
-		if _, err := os.Stat(localTemplatesDir); err == nil {
-			return localTemplatesDir, nil
-		}
+// TemplatesFS returns the built-in templates.
+// When running from source (development) the local internal/assets/templates
+// directory is used so template edits don't need a rebuild.
+func TemplatesFS() fs.FS {
+	if localDir := localAssetsDir("templates"); localDir != "" {
+		return os.DirFS(localDir)
 	}
+	templates, _ := fs.Sub(templatesFS, "templates")
+	return templates
+}
 
-	// If not found locally, extract embedded files to temp directory
-	tempDir, err := os.MkdirTemp("", "claude-helper-templates-*")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp directory: %w", err)
+// SoundsFS returns the built-in sounds, preferring local internal/assets/sounds in development
+func SoundsFS() fs.FS {
+	if localDir := localAssetsDir("sounds"); localDir != "" {
+		return os.DirFS(localDir)
 	}
+	sounds, _ := fs.Sub(soundsFS, "sounds")
+	return sounds
+}
 
-	// Extract embedded templates to temp directory
-	err = fs.WalkDir(templatesFS, "templates", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Create the target path in temp directory
-		targetPath := filepath.Join(tempDir, path)
-
-		if d.IsDir() {
-			return os.MkdirAll(targetPath, 0755)
-		}
-
-		// Read file content from embedded FS
-		content, err := templatesFS.ReadFile(path)
-		if err != nil {
-			return err
-		}
-
-		// Create parent directory if it doesn't exist
-		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-			return err
-		}
-
-		// Write file to temp location
-		return os.WriteFile(targetPath, content, 0644)
-	})
-
+// localAssetsDir returns internal/assets/<name> in the working directory if it exists
+func localAssetsDir(name string) string {
+	wd, err := os.Getwd()
 	if err != nil {
-		os.RemoveAll(tempDir) // Clean up on error
-		return "", fmt.Errorf("failed to extract embedded templates: %w", err)
+		return ""
 	}
-
-	return filepath.Join(tempDir, "templates"), nil
+	localDir := filepath.Join(wd, "internal", "assets", name)
+	if info, err := os.Stat(localDir); err == nil && info.IsDir() {
+		return localDir
+	}
+	return ""
 }
 
 // ListAgentTemplates returns a list of available agent templates
 func ListAgentTemplates() ([]string, error) {
-	templatesDir, err := GetTemplatesDir()
-	if err != nil {
-		return nil, err
-	}
-
-	agentsDir := filepath.Join(templatesDir, "agents")
-	entries, err := os.ReadDir(agentsDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read agents directory: %w", err)
-	}
-
-	var agents []string
-	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".md" {
-			name := entry.Name()[:len(entry.Name())-3] // Remove .md extension
-			agents = append(agents, name)
-		}
-	}
-
-	return agents, nil
+	return listTemplateNames("agent")
 }
 
 // ListHookTemplates returns a list of available hook templates
 func ListHookTemplates() ([]string, error) {
-	templatesDir, err := GetTemplatesDir()
-	if err != nil {
-		return nil, err
-	}
-
-	hooksDir := filepath.Join(templatesDir, "hooks")
-	entries, err := os.ReadDir(hooksDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read hooks directory: %w", err)
-	}
-
-	var hooks []string
-	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".yaml" {
-			name := entry.Name()[:len(entry.Name())-5] // Remove .yaml extension
-			hooks = append(hooks, name)
-		}
-	}
-
-	return hooks, nil
+	return listTemplateNames("hook")
 }
 
-// GetTemplatePath returns the full path to a template file
-func GetTemplatePath(templateType, name string) (string, error) {
-	templatesDir, err := GetTemplatesDir()
+func listTemplateNames(templateType string) ([]string, error) {
+	templates, err := ListTemplates(templateType)
 	if err != nil {
-		return "", err
-	}
-
-	var templatePath string
-	switch templateType {
-	case "agent":
-		templatePath = filepath.Join(templatesDir, "agents", name+".md")
-	case "hook":
-		templatePath = filepath.Join(templatesDir, "hooks", name+".yaml")
-	default:
-		return "", fmt.Errorf("unknown template type: %s", templateType)
+		return nil, err
 	}
 
-	if _, err := os.Stat(templatePath); err != nil {
-		return "", fmt.Errorf("template not found: %s", templatePath)
+	var names []string
+	for _, template := range templates {
+		names = append(names, template.Name)
 	}
+	return names, nil
+}
 
-	return templatePath, nil
+// ReadSound returns the content of a built-in sound file
+func ReadSound(filename string) ([]byte, error) {
+	return fs.ReadFile(SoundsFS(), filename)
 }
 
-// GetSoundsDir returns the path to the sounds directory
-// If running from source (development), it uses the local internal/assets/sounds
-// If running from built binary, it extracts embedded sounds to a temp location
-func GetSoundsDir() (string, error) {
-	// First try to find local sounds directory (for development)
-	if wd, err := os.Getwd(); err == nil {
-		localSoundsDir := filepath.Join(wd, "internal", "assets", "sounds")
-		if _, err := os.Stat(localSoundsDir); err == nil {
-			return localSoundsDir, nil
+// GetSoundFilePath returns an on-disk path to a built-in sound, for audio players
+// that need a file. Embedded sounds are written once to the user cache directory.
+func GetSoundFilePath(filename string) (string, error) {
+	if localDir := localAssetsDir("sounds"); localDir != "" {
+		soundPath := filepath.Join(localDir, filename)
+		if _, err := os.Stat(soundPath); err != nil {
+			return "", fmt.Errorf("sound file not found: %s", soundPath)
 		}
+		return soundPath, nil
 	}
 
-	// If not found locally, extract embedded files to temp directory
-	tempDir, err := os.MkdirTemp("", "claude-helper-sounds-*")
+	content, err := ReadSound(filename)
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp directory: %w", err)
+		return "", fmt.Errorf("sound file not found: %s", filename)
 	}
 
-	// Extract embedded sounds to temp directory
-	err = fs.WalkDir(soundsFS, "sounds", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Create the target path in temp directory
-		targetPath := filepath.Join(tempDir, path)
-
-		if d.IsDir() {
-			return os.MkdirAll(targetPath, 0755)
-		}
-
-		// Read file content from embedded FS
-		content, err := soundsFS.ReadFile(path)
-		if err != nil {
-			return err
-		}
-
-		// Create parent directory if it doesn't exist
-		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-			return err
-		}
-
-		// Write file to temp location
-		return os.WriteFile(targetPath, content, 0644)
-	})
-
+	cacheDir, err := os.UserCacheDir()
 	if err != nil {
-		os.RemoveAll(tempDir) // Clean up on error
-		return "", fmt.Errorf("failed to extract embedded sounds: %w", err)
+		return "", fmt.Errorf("failed to get cache directory: %w", err)
 	}
+	soundPath := filepath.Join(cacheDir, "claude-helper", "sounds", filename)
 
-	return filepath.Join(tempDir, "sounds"), nil
-}
-
-// GetSoundFilePath returns the full path to a sound file
-func GetSoundFilePath(filename string) (string, error) {
-	soundsDir, err := GetSoundsDir()
-	if err != nil {
-		return "", err
+	// Reuse the cached copy unless the embedded sound changed
+	if cached, err := os.ReadFile(soundPath); err == nil && config.HashBytes(cached) == config.HashBytes(content) {
+		return soundPath, nil
 	}
 
-	soundPath := filepath.Join(soundsDir, filename)
-	
-	if _, err := os.Stat(soundPath); err != nil {
-		return "", fmt.Errorf("sound file not found: %s", soundPath)
+	if err := os.MkdirAll(filepath.Dir(soundPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create sounds cache directory: %w", err)
+	}
+	if err := os.WriteFile(soundPath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write sound file: %w", err)
 	}
-
 	return soundPath, nil
 }
 
@@ -0,0 +1,332 @@
+package assets
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+// Template origins, listed in lookup priority order
+const (
+	OriginProject = "project"
+	OriginUser    = "user"
+	OriginBuiltin = "built-in"
+)
+
+// ManifestSuffix marks per-component manifest files (<name>.manifest.yaml)
+const ManifestSuffix = ".manifest"
+
+// TemplateDir is a file system containing agents/, hooks/, commands/, mcp/, fragments/ and bundles/ subdirectories
+type TemplateDir struct {
+	FS     fs.FS
+	Path   string // On-disk directory backing FS, empty for embedded templates
+	Origin string
+}
+
+// Template describes a template file found in one of the template directories
+type Template struct {
+	Name   string
+	Type   string
+	Path   string // Slash-separated path within FS, e.g. agents/code-reviewer.md
+	FS     fs.FS  // Template directory the file was found in
+	Root   string // On-disk directory backing FS, empty for embedded templates
+	Origin string
+}
+
+// ReadFile returns the content of the template file
+func (t *Template) ReadFile() ([]byte, error) {
+	return fs.ReadFile(t.FS, t.Path)
+}
+
+// ReadSibling returns the content of another file in the template's directory
+func (t *Template) ReadSibling(name string) ([]byte, error) {
+	return fs.ReadFile(t.FS, path.Join(path.Dir(t.Path), name))
+}
+
+// ManifestPath returns the path of the template's own <name>.manifest.yaml within FS
+func (t *Template) ManifestPath() string {
+	return strings.TrimSuffix(t.Path, path.Ext(t.Path)) + ManifestSuffix + ".yaml"
+}
+
+// Location returns a human-readable location of the template for messages
+func (t *Template) Location() string {
+	if t.Root == "" {
+		return t.Origin + ":" + t.Path
+	}
+	return filepath.Join(t.Root, filepath.FromSlash(t.Path))
+}
+
+// dirTemplateDir returns a TemplateDir backed by an on-disk directory
+func dirTemplateDir(dir, origin string) TemplateDir {
+	return TemplateDir{FS: os.DirFS(dir), Path: dir, Origin: origin}
+}
+
+// GetProjectTemplatesDir returns the project-level user template directory (.claude/templates)
+func GetProjectTemplatesDir() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return filepath.Join(wd, ".claude", "templates"), nil
+}
+
+// GetUserTemplatesDir returns the user-level template directory (~/.config/claude-helper/templates)
+func GetUserTemplatesDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "claude-helper", "templates"), nil
+}
+
+// GetTemplateDirs returns every template directory in priority order.
+// Project templates shadow user templates, which shadow configured template
+// sources (in their own priority order), which shadow built-in templates.
+func GetTemplateDirs() ([]TemplateDir, error) {
+	var dirs []TemplateDir
+
+	if projectDir, err := GetProjectTemplatesDir(); err == nil {
+		dirs = append(dirs, dirTemplateDir(projectDir, OriginProject))
+	}
+	if userDir, err := GetUserTemplatesDir(); err == nil {
+		dirs = append(dirs, dirTemplateDir(userDir, OriginUser))
+	}
+
+	sources, err := config.LoadTemplateSources()
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range sources {
+		sourceDir, err := config.GetSourceTemplatesDir(source)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, dirTemplateDir(sourceDir, source.Name))
+	}
+
+	builtin := TemplateDir{FS: TemplatesFS(), Origin: OriginBuiltin}
+	if localDir := localAssetsDir("templates"); localDir != "" {
+		builtin.Path = localDir
+	}
+	dirs = append(dirs, builtin)
+
+	return dirs, nil
+}
+
+// TemplateLocation returns the subdirectory and file extension used for a template type
+func TemplateLocation(templateType string) (subdir string, ext string, err error) {
+	switch templateType {
+	case "agent":
+		return "agents", ".md", nil
+	case "hook":
+		return "hooks", ".yaml", nil
+	case "command":
+		return "commands", ".md", nil
+	case "mcp":
+		return "mcp", ".yaml", nil
+	case "permission":
+		return "permissions", ".yaml", nil
+	case "fragment":
+		return "fragments", ".md", nil
+	case "bundle":
+		return "bundles", ".yaml", nil
+	default:
+		return "", "", fmt.Errorf("unknown template type: %s", templateType)
+	}
+}
+
+// TemplateExtensions returns the file extensions accepted for a template type,
+// the primary extension first
+func TemplateExtensions(templateType string) ([]string, error) {
+	_, ext, err := TemplateLocation(templateType)
+	if err != nil {
+		return nil, err
+	}
+	if templateType == "hook" || templateType == "mcp" || templateType == "permission" {
+		// Hooks, MCP servers and permission presets use the same schema in either encoding
+		return []string{ext, ".json"}, nil
+	}
+	return []string{ext}, nil
+}
+
+// SplitQualifiedName splits a "source/name" reference into its source and name.
+// Unqualified names return an empty source.
+func SplitQualifiedName(ref string) (source string, name string) {
+	if i := strings.Index(ref, "/"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return "", ref
+}
+
+// FindTemplate looks up a template by type and name across all template directories.
+// A qualified "source/name" reference only looks in the named source.
+func FindTemplate(templateType, ref string) (*Template, error) {
+	subdir, ext, err := TemplateLocation(templateType)
+	if err != nil {
+		return nil, err
+	}
+	exts, err := TemplateExtensions(templateType)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs, err := GetTemplateDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	sourceName, name := SplitQualifiedName(ref)
+	if sourceName != "" {
+		found := false
+		for _, dir := range dirs {
+			if dir.Origin == sourceName {
+				dirs = []TemplateDir{dir}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown template source: %s", sourceName)
+		}
+	}
+
+	file := name
+	if templateType == "command" {
+		// Namespaced commands live in subdirectories
+		file = strings.TrimSuffix(types.CommandFilePath(name), ".md")
+	}
+
+	for _, dir := range dirs {
+		for _, candidate := range exts {
+			templatePath := path.Join(subdir, file+candidate)
+			if info, err := fs.Stat(dir.FS, templatePath); err == nil && !info.IsDir() {
+				return &Template{Name: name, Type: templateType, Path: templatePath, FS: dir.FS, Root: dir.Path, Origin: dir.Origin}, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("template not found: %s/%s%s", subdir, file, ext)
+}
+
+// ListTemplates returns all templates of a type, sorted by name.
+// When several directories provide the same name only the highest priority one is returned.
+func ListTemplates(templateType string) ([]Template, error) {
+	subdir, _, err := TemplateLocation(templateType)
+	if err != nil {
+		return nil, err
+	}
+	exts, err := TemplateExtensions(templateType)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs, err := GetTemplateDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var templates []Template
+	for _, dir := range dirs {
+		files, err := templateFiles(dir.FS, subdir, templateType == "command")
+		if err != nil {
+			continue // Missing user directories are normal
+		}
+
+		// Files are sorted by name, so an encoding preferred by exts has to be picked explicitly
+		found := make(map[string]string)
+		var names []string
+		for _, file := range files {
+			ext := strings.ToLower(path.Ext(file))
+			rank := indexOf(exts, ext)
+			if rank < 0 {
+				continue
+			}
+			name := strings.TrimSuffix(file, path.Ext(file))
+			if strings.HasSuffix(name, ManifestSuffix) {
+				continue
+			}
+			if templateType == "command" {
+				name = types.CommandNameFromPath(file)
+			}
+			if previous, ok := found[name]; ok {
+				if indexOf(exts, strings.ToLower(path.Ext(previous))) <= rank {
+					continue
+				}
+			} else {
+				names = append(names, name)
+			}
+			found[name] = file
+		}
+
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			templates = append(templates, Template{
+				Name:   name,
+				Type:   templateType,
+				Path:   path.Join(subdir, found[name]),
+				FS:     dir.FS,
+				Root:   dir.Path,
+				Origin: dir.Origin,
+			})
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// templateFiles returns the files in a template subdirectory, relative to it.
+// Subdirectories are only walked when recursive is set, for namespaced commands.
+func templateFiles(fsys fs.FS, subdir string, recursive bool) ([]string, error) {
+	if !recursive {
+		entries, err := fs.ReadDir(fsys, subdir)
+		if err != nil {
+			return nil, err
+		}
+		var files []string
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, entry.Name())
+			}
+		}
+		return files, nil
+	}
+
+	if _, err := fs.Stat(fsys, subdir); err != nil {
+		return nil, err
+	}
+	var files []string
+	err := fs.WalkDir(fsys, subdir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			files = append(files, strings.TrimPrefix(file, subdir+"/"))
+		}
+		return nil
+	})
+	return files, err
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,349 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// parseSetFlags converts repeated --set key=value flags into a map
+func parseSetFlags(values []string) (map[string]string, error) {
+	params := make(map[string]string)
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set value '%s'. Use key=value", value)
+		}
+		params[key] = val
+	}
+	return params, nil
+}
+
+// agentVariablePattern matches a {{.name}} placeholder, with optional spaces inside the braces
+var agentVariablePattern = regexp.MustCompile(`\{\{\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// renderAgentTemplate resolves the variables declared in an agent template's
+// frontmatter and replaces their {{.name}} placeholders. Any other {{ in the
+// agent, such as a code sample, is kept as written.
+//
+// Values are taken from overrides (--set) first, then from the values remembered
+// from a previous install, and finally asked interactively with the declared default.
+// When interactive is false the declared default is used without asking.
+// Unlike overrides, remembered values the template no longer declares are ignored.
+func renderAgentTemplate(name string, content []byte, overrides, remembered map[string]string, interactive bool) (string, map[string]string, error) {
+	frontmatter, body, err := types.SplitFrontmatter(string(content))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var meta types.AgentFrontmatter
+	if err := yaml.Unmarshal([]byte(frontmatter), &meta); err != nil {
+		return "", nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	// Templates without variables are copied verbatim
+	if len(meta.Variables) == 0 {
+		if len(overrides) > 0 {
+			return "", nil, fmt.Errorf("agent '%s' does not declare any variables", name)
+		}
+		return string(content), nil, nil
+	}
+
+	values, err := resolveAgentVariables(name, meta.Variables, overrides, remembered, interactive)
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanFrontmatter, err := stripFrontmatterKey(frontmatter, "variables")
+	if err != nil {
+		return "", nil, err
+	}
+
+	source := "---\n" + cleanFrontmatter + "---\n\n" + body
+	rendered := agentVariablePattern.ReplaceAllStringFunc(source, func(placeholder string) string {
+		if value, ok := values[agentVariablePattern.FindStringSubmatch(placeholder)[1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+
+	return rendered, values, nil
+}
+
+func resolveAgentVariables(name string, variables []types.AgentVariable, overrides, remembered map[string]string, interactive bool) (map[string]string, error) {
+	declared := make(map[string]bool)
+	for _, variable := range variables {
+		declared[variable.Name] = true
+	}
+	for key := range overrides {
+		if !declared[key] {
+			return nil, fmt.Errorf("agent '%s' has no variable named '%s'", name, key)
+		}
+	}
+
+	values := make(map[string]string)
+	reader := bufio.NewReader(os.Stdin)
+
+	for _, variable := range variables {
+		if value, ok := overrides[variable.Name]; ok {
+			values[variable.Name] = value
+			continue
+		}
+		if value, ok := remembered[variable.Name]; ok {
+			if interactive {
+				fmt.Printf("Using saved value for '%s': %s\n", variable.Name, value)
+			}
+			values[variable.Name] = value
+			continue
+		}
+
+		value := variable.Default
+		if interactive {
+			prompt := variable.Prompt
+			if prompt == "" {
+				prompt = fmt.Sprintf("Value for '%s'", variable.Name)
+			}
+			fmt.Printf("%s [%s]: ", prompt, variable.Default)
+
+			input, err := reader.ReadString('\n')
+			if err != nil {
+				// No interactive input available, fall back to defaults
+				fmt.Println()
+				interactive = false
+			} else if input = strings.TrimSpace(input); input != "" {
+				value = input
+			}
+		}
+		values[variable.Name] = value
+	}
+
+	return values, nil
+}
+
+// stripFrontmatterKey removes a top-level key from YAML frontmatter while
+// keeping the order of the remaining keys
+func stripFrontmatterKey(frontmatter, key string) (string, error) {
+	mapping, err := parseFrontmatterMapping(frontmatter)
+	if err != nil {
+		return "", err
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			break
+		}
+	}
+
+	out, err := yaml.Marshal(mapping)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	return string(out), nil
+}
+
+// includeDirective matches {{include "fragment"}} directives in agent markdown
+var includeDirective = regexp.MustCompile(`\{\{-?\s*include\s+"([^"]+)"\s*-?\}\}`)
+
+// templateVerifier checks a parent agent or fragment before its content is used
+type templateVerifier func(template *assets.Template) error
+
+// flattenAgentTemplate resolves the extends: chain and {{include}} directives of
+// an agent template into a single self-contained markdown document. Parents and
+// fragments may come from other sources than the agent, so when installing each
+// is passed to verify; previews pass nil.
+func flattenAgentTemplate(name string, content []byte, verify templateVerifier) ([]byte, error) {
+	return flattenAgent(name, content, nil, verify)
+}
+
+func flattenAgent(name string, content []byte, chain []string, verify templateVerifier) ([]byte, error) {
+	for _, seen := range chain {
+		if seen == name {
+			return nil, fmt.Errorf("agent inheritance cycle: %s -> %s", strings.Join(chain, " -> "), name)
+		}
+	}
+	chain = append(chain, name)
+
+	frontmatter, body, err := types.SplitFrontmatter(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("agent '%s': %w", name, err)
+	}
+
+	body, err = expandIncludes(body, nil, verify)
+	if err != nil {
+		return nil, fmt.Errorf("agent '%s': %w", name, err)
+	}
+
+	var meta types.AgentFrontmatter
+	if err := yaml.Unmarshal([]byte(frontmatter), &meta); err != nil {
+		return nil, fmt.Errorf("agent '%s': failed to parse frontmatter: %w", name, err)
+	}
+
+	if meta.Extends == "" {
+		return []byte("---\n" + frontmatter + "\n---\n\n" + body), nil
+	}
+
+	parent, err := assets.FindTemplate("agent", meta.Extends)
+	if err != nil {
+		return nil, fmt.Errorf("agent '%s' extends unknown agent '%s'", name, meta.Extends)
+	}
+	if verify != nil {
+		if err := verify(parent); err != nil {
+			return nil, fmt.Errorf("parent agent '%s': %w", meta.Extends, err)
+		}
+	}
+	parentContent, err := parent.ReadFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parent agent '%s': %w", meta.Extends, err)
+	}
+
+	parentFlat, err := flattenAgent(meta.Extends, parentContent, chain, verify)
+	if err != nil {
+		return nil, err
+	}
+	parentFrontmatter, parentBody, err := types.SplitFrontmatter(string(parentFlat))
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := mergeFrontmatter(parentFrontmatter, frontmatter)
+	if err != nil {
+		return nil, fmt.Errorf("agent '%s': %w", name, err)
+	}
+
+	body = strings.TrimRight(parentBody, "\n") + "\n\n" + body
+	return []byte("---\n" + merged + "---\n\n" + body), nil
+}
+
+// expandIncludes replaces {{include "name"}} directives with the contents of
+// templates/fragments/<name>.md, recursively
+func expandIncludes(text string, stack []string, verify templateVerifier) (string, error) {
+	var expandErr error
+	result := includeDirective.ReplaceAllStringFunc(text, func(directive string) string {
+		if expandErr != nil {
+			return directive
+		}
+		fragment := includeDirective.FindStringSubmatch(directive)[1]
+
+		for _, seen := range stack {
+			if seen == fragment {
+				expandErr = fmt.Errorf("fragment include cycle: %s -> %s", strings.Join(stack, " -> "), fragment)
+				return directive
+			}
+		}
+
+		fragmentTemplate, err := assets.FindTemplate("fragment", fragment)
+		if err != nil {
+			expandErr = fmt.Errorf("fragment '%s' not found", fragment)
+			return directive
+		}
+		if verify != nil {
+			if err := verify(fragmentTemplate); err != nil {
+				expandErr = fmt.Errorf("fragment '%s': %w", fragment, err)
+				return directive
+			}
+		}
+		content, err := fragmentTemplate.ReadFile()
+		if err != nil {
+			expandErr = fmt.Errorf("failed to read fragment '%s': %w", fragment, err)
+			return directive
+		}
+
+		expanded, err := expandIncludes(string(content), append(stack, fragment), verify)
+		if err != nil {
+			expandErr = err
+			return directive
+		}
+		return strings.TrimRight(expanded, "\n")
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return result, nil
+}
+
+// mergeFrontmatter overlays a child agent's frontmatter on its parent's.
+// Child keys replace parent keys, variables are merged by name, and the
+// extends key is dropped from the result.
+func mergeFrontmatter(parent, child string) (string, error) {
+	parentMapping, err := parseFrontmatterMapping(parent)
+	if err != nil {
+		return "", err
+	}
+	childMapping, err := parseFrontmatterMapping(child)
+	if err != nil {
+		return "", err
+	}
+
+	for i := 0; i+1 < len(childMapping.Content); i += 2 {
+		key, value := childMapping.Content[i], childMapping.Content[i+1]
+		if key.Value == "extends" {
+			continue
+		}
+
+		existing := mappingValue(parentMapping, key.Value)
+		switch {
+		case existing == nil:
+			parentMapping.Content = append(parentMapping.Content, key, value)
+		case key.Value == "variables" && existing.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode:
+			mergeVariableNodes(existing, value)
+		default:
+			*existing = *value
+		}
+	}
+
+	out, err := yaml.Marshal(parentMapping)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	return string(out), nil
+}
+
+func mergeVariableNodes(parent, child *yaml.Node) {
+	for _, childVar := range child.Content {
+		name := mappingValue(childVar, "name")
+		replaced := false
+		if name != nil {
+			for i, parentVar := range parent.Content {
+				if parentName := mappingValue(parentVar, "name"); parentName != nil && parentName.Value == name.Value {
+					parent.Content[i] = childVar
+					replaced = true
+					break
+				}
+			}
+		}
+		if !replaced {
+			parent.Content = append(parent.Content, childVar)
+		}
+	}
+}
+
+func parseFrontmatterMapping(frontmatter string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(frontmatter), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("invalid agent format: frontmatter is not a mapping")
+	}
+	return doc.Content[0], nil
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
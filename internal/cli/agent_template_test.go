@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderAgentTemplate(t *testing.T) {
+	const agent = `---
+name: reviewer
+description: Reviews code
+variables:
+  - name: language
+    default: Go
+  - name: strictness
+    default: normal
+---
+
+Review {{.language}} code with {{ .strictness }} strictness.
+`
+
+	tests := []struct {
+		name       string
+		content    string
+		overrides  map[string]string
+		remembered map[string]string
+		want       string
+		wantErr    string
+	}{
+		{
+			name:    "defaults",
+			content: agent,
+			want:    "Review Go code with normal strictness.",
+		},
+		{
+			name:      "overrides",
+			content:   agent,
+			overrides: map[string]string{"language": "Rust"},
+			want:      "Review Rust code with normal strictness.",
+		},
+		{
+			name:       "remembered values",
+			content:    agent,
+			remembered: map[string]string{"strictness": "high"},
+			want:       "Review Go code with high strictness.",
+		},
+		{
+			name:       "overrides win over remembered values",
+			content:    agent,
+			overrides:  map[string]string{"strictness": "low"},
+			remembered: map[string]string{"strictness": "high"},
+			want:       "Review Go code with low strictness.",
+		},
+		{
+			name:       "remembered values no longer declared are ignored",
+			content:    agent,
+			remembered: map[string]string{"language": "Rust", "conventions": "docs/style.md"},
+			want:       "Review Rust code with normal strictness.",
+		},
+		{
+			name:       "remembered values without variables are ignored",
+			content:    "---\nname: reviewer\ndescription: Reviews code\n---\n\nReview code.\n",
+			remembered: map[string]string{"language": "Rust"},
+			want:       "Review code.",
+		},
+		{
+			name:    "literal braces are kept",
+			content: agent + "Example: {{#each items}}{{this}}{{/each}} and {{ range .Items }}.\n",
+			want:    "Example: {{#each items}}{{this}}{{/each}} and {{ range .Items }}.",
+		},
+		{
+			name:    "undeclared placeholders are kept",
+			content: agent + "Jinja: {{.other}} and {{ user.name }}\n",
+			want:    "Jinja: {{.other}} and {{ user.name }}",
+		},
+		{
+			name:      "values are not expanded again",
+			content:   agent,
+			overrides: map[string]string{"language": "{{.strictness}}"},
+			want:      "Review {{.strictness}} code with normal strictness.",
+		},
+		{
+			name:      "unknown override",
+			content:   agent,
+			overrides: map[string]string{"lang": "Rust"},
+			wantErr:   "has no variable named 'lang'",
+		},
+		{
+			name:      "override without variables",
+			content:   "---\nname: reviewer\ndescription: Reviews code\n---\n\nReview code.\n",
+			overrides: map[string]string{"language": "Rust"},
+			wantErr:   "does not declare any variables",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempProject(t)
+
+			got, _, err := renderAgentTemplate("reviewer", []byte(tt.content), tt.overrides, tt.remembered, false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("renderAgentTemplate() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderAgentTemplate() error = %v", err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("renderAgentTemplate() = %q, want it to contain %q", got, tt.want)
+			}
+			if strings.Contains(got, "variables:") {
+				t.Errorf("renderAgentTemplate() kept the variables frontmatter: %q", got)
+			}
+		})
+	}
+}
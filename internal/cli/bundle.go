@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// bundlePrefix marks a bundle reference on the command line, e.g. bundle:essentials
+const bundlePrefix = "bundle:"
+
+// loadBundle finds and parses a bundle template
+func loadBundle(ref string) (*types.Bundle, error) {
+	template, err := assets.FindTemplate("bundle", ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bundle '%s': %w", ref, err)
+	}
+
+	content, err := template.ReadFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	var bundle types.Bundle
+	if err := yaml.Unmarshal(content, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle '%s': %w", ref, err)
+	}
+	if bundle.Name == "" {
+		bundle.Name = template.Name
+	}
+
+	if len(bundle.Components) == 0 {
+		return nil, fmt.Errorf("bundle '%s' lists no components", ref)
+	}
+	for _, component := range bundle.Components {
+		if component.Name == "" {
+			return nil, fmt.Errorf("bundle '%s' has a component without a name", ref)
+		}
+		if component.Scope != "" && component.Scope != config.ScopeProject && component.Scope != config.ScopeUser {
+			return nil, fmt.Errorf("bundle '%s': invalid scope '%s' for %s (use project or user)", ref, component.Scope, component.Name)
+		}
+	}
+
+	return &bundle, nil
+}
+
+// installBundle installs every component in a bundle. Components that are
+// already installed are skipped and not recorded as belonging to the bundle.
+func installBundle(ref string, opts installOptions) error {
+	bundle, err := loadBundle(ref)
+	if err != nil {
+		return err
+	}
+	_, bundleName := assets.SplitQualifiedName(ref)
+
+	if len(opts.Params) > 0 {
+		return fmt.Errorf("--set is not supported for bundles; set params in the bundle file instead")
+	}
+
+	fmt.Printf("Installing bundle: %s (%d components)\n", bundleName, len(bundle.Components))
+
+	lock, err := config.LoadLockfile()
+	if err != nil {
+		return err
+	}
+	locked := lock.Bundles[bundleName]
+	owned := make(map[string]bool)
+	for _, key := range locked.Components {
+		owned[key] = true
+	}
+
+	var installed, failed []string
+	for _, component := range bundle.Components {
+		_, name := assets.SplitQualifiedName(component.Name)
+		scope := component.Scope
+		if scope == config.ScopeProject {
+			scope = ""
+		}
+
+		fmt.Println()
+		wasInstalled := false
+		var key string
+		if template, err := findComponentTemplate(component.Name); err == nil {
+			key = types.LockKey(template.Type, name, scope)
+			wasInstalled, _ = isComponentInstalled(name, template.Type, scope)
+			if wasInstalled && !opts.Force {
+				fmt.Printf("⏭️  %s '%s' is already installed, skipping\n", template.Type, name)
+				continue
+			}
+		}
+
+		err := installComponentFromTemplate(component.Name, installOptions{
+			Force:           opts.Force,
+			Params:          component.Params,
+			IgnoreCompat:    opts.IgnoreCompat,
+			Scope:           scope,
+			AllowUnverified: opts.AllowUnverified,
+		})
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			failed = append(failed, name)
+			continue
+		}
+		installed = append(installed, name)
+
+		// Remember what this bundle installed so 'cchp remove bundle:<name>' removes only that
+		if !wasInstalled && !owned[key] {
+			locked.Components = append(locked.Components, key)
+			owned[key] = true
+		}
+	}
+
+	locked.Template = ref
+	if len(locked.Components) > 0 {
+		if err := config.RecordLockedBundle(bundleName, locked); err != nil {
+			fmt.Printf("Warning: failed to update lockfile: %v\n", err)
+		}
+	}
+
+	fmt.Println()
+	if len(failed) > 0 {
+		return fmt.Errorf("bundle '%s': failed to install %s", bundleName, strings.Join(failed, ", "))
+	}
+
+	fmt.Printf("✓ Successfully installed bundle '%s' (%d installed, %d skipped)\n",
+		bundleName, len(installed), len(bundle.Components)-len(installed))
+	return nil
+}
+
+// removeBundle removes the components a bundle installed and forgets the bundle
+func removeBundle(name string, skipConfirm, cascade bool) error {
+	lock, err := config.LoadLockfile()
+	if err != nil {
+		return err
+	}
+
+	locked, ok := lock.Bundles[name]
+	if !ok {
+		return fmt.Errorf("bundle '%s' is not installed", name)
+	}
+
+	// Components removed individually since the bundle was installed are skipped
+	var components []string
+	for _, component := range locked.Components {
+		if _, ok := lock.Components[component]; ok {
+			components = append(components, component)
+		}
+	}
+
+	fmt.Printf("Removing bundle: %s\n", name)
+	if len(components) > 0 {
+		fmt.Printf("Components installed by this bundle: %s\n", strings.Join(components, ", "))
+	}
+
+	// Components outside the bundle may still need what it installed
+	ordered, dependents, err := collectRemovals(components)
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		if !cascade {
+			return fmt.Errorf("bundle '%s' installed components required by %s. Use --cascade to remove them as well", name, strings.Join(dependents, ", "))
+		}
+		fmt.Printf("Also removing components that require them: %s\n", strings.Join(dependents, ", "))
+	}
+
+	if !skipConfirm && len(components) > 0 {
+		if !confirmRemoval(name, "bundle", "") {
+			fmt.Println("Removal cancelled.")
+			return nil
+		}
+	}
+
+	var failed []string
+	for _, component := range ordered {
+		componentType, componentName, scope := types.SplitLockKey(component)
+
+		// Already gone from .claude; just forget it
+		if installed, err := isComponentInstalled(componentName, componentType, scope); err == nil && !installed {
+			if err := config.RemoveLockedComponent(component); err != nil {
+				return err
+			}
+			fmt.Printf("⏭️  %s '%s' is no longer installed\n", componentType, componentName)
+			continue
+		}
+
+		if err := removeInstalledComponent(componentName, componentType, scope); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			failed = append(failed, component)
+			continue
+		}
+		fmt.Printf("✓ Removed %s '%s'\n", componentType, componentName)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("bundle '%s': failed to remove %s", name, strings.Join(failed, ", "))
+	}
+
+	if err := config.RemoveLockedBundle(name); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Successfully removed bundle '%s'\n", name)
+	return nil
+}
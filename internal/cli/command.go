@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+var commandCmd = &cobra.Command{
+	Use:   "command",
+	Short: "Work with slash commands",
+	Long:  `Commands for developing custom slash commands (.claude/commands/*.md).`,
+}
+
+var commandLintCmd = &cobra.Command{
+	Use:   "lint [name|path...]",
+	Short: "Check slash commands for mistakes",
+	Long: `Check custom slash commands for mistakes Claude Code won't report:
+
+  - frontmatter that doesn't parse, or keys Claude Code doesn't know
+  - a missing description or an empty prompt
+  - $ARGUMENTS or $1..$9 without an argument-hint, or a hint nobody uses
+  - !` + "`command`" + ` lines without Bash in allowed-tools
+  - allowed-tools naming tools that don't exist, and unknown models
+  - @file references to files that don't exist
+  - names that aren't valid or that a built-in command shadows
+
+Arguments are command names (installed commands first, then templates), files
+or directories. Without arguments the project's .claude/commands and
+.claude/templates/commands are checked. Fails when a command can't work as written.`,
+	RunE: lintCommands,
+}
+
+func init() {
+	rootCmd.AddCommand(commandCmd)
+	commandCmd.AddCommand(commandLintCmd)
+}
+
+// commandLintTarget is a command file to lint
+type commandLintTarget struct {
+	Name    string
+	Path    string // Shown in the report
+	Content []byte
+}
+
+// lintIssue is a problem found by a lint or check command
+type lintIssue struct {
+	Error   bool // It doesn't work as written; otherwise it's a warning
+	Message string
+	Rules   []string // Permission rules the issue is about
+}
+
+// builtinSlashCommands are Claude Code's own commands, which take precedence over custom ones
+var builtinSlashCommands = map[string]bool{
+	"add-dir": true, "agents": true, "bug": true, "clear": true, "compact": true, "config": true,
+	"context": true, "cost": true, "doctor": true, "exit": true, "export": true, "help": true,
+	"hooks": true, "init": true, "login": true, "logout": true, "mcp": true, "memory": true,
+	"model": true, "permissions": true, "pr-comments": true, "release-notes": true, "resume": true,
+	"review": true, "rewind": true, "status": true, "statusline": true, "todos": true, "usage": true,
+	"vim": true,
+}
+
+var (
+	commandArgumentsPattern = regexp.MustCompile(`\$(ARGUMENTS|[1-9])\b`)
+	commandBashPattern      = regexp.MustCompile("!`([^`]+)`")
+	commandFileRefPattern   = regexp.MustCompile(`(?:^|\s)@([\w./-]*[./][\w./-]*)`)
+)
+
+func lintCommands(cmd *cobra.Command, args []string) error {
+	targets, err := commandLintTargets(args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Println("No slash commands found.")
+		return nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	fmt.Printf("🔍 Linting %d slash command(s)...\n", len(targets))
+	warnings, failures := 0, 0
+	for _, target := range targets {
+		issues := lintCommand(target.Name, target.Content, wd)
+		if len(issues) == 0 {
+			fmt.Printf("✓ %s\n", target.Name)
+			continue
+		}
+		fmt.Printf("\n%s (%s):\n", target.Name, target.Path)
+		for _, issue := range issues {
+			if issue.Error {
+				failures++
+				fmt.Printf("  ❌ %s\n", issue.Message)
+			} else {
+				warnings++
+				fmt.Printf("  ⚠️  %s\n", issue.Message)
+			}
+		}
+	}
+
+	fmt.Println()
+	if failures == 0 && warnings == 0 {
+		fmt.Println("✓ No problems found")
+		return nil
+	}
+	fmt.Printf("Found %d error(s) and %d warning(s)\n", failures, warnings)
+	if failures > 0 {
+		return fmt.Errorf("%d problem(s) stop commands from working as written", failures)
+	}
+	return nil
+}
+
+// commandLintTargets resolves lint's arguments to command files
+func commandLintTargets(args []string) ([]commandLintTarget, error) {
+	if len(args) == 0 {
+		var targets []commandLintTarget
+		commandsDir, err := config.GetProjectCommandsPath()
+		if err != nil {
+			return nil, err
+		}
+		templatesDir, err := assets.GetProjectTemplatesDir()
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range []string{commandsDir, filepath.Join(templatesDir, "commands")} {
+			if _, err := os.Stat(dir); err != nil {
+				continue
+			}
+			dirTargets, err := commandLintDir(dir)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, dirTargets...)
+		}
+		return targets, nil
+	}
+
+	var targets []commandLintTarget
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil {
+			if info.IsDir() {
+				dirTargets, err := commandLintDir(arg)
+				if err != nil {
+					return nil, err
+				}
+				targets = append(targets, dirTargets...)
+				continue
+			}
+			content, err := os.ReadFile(arg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", arg, err)
+			}
+			targets = append(targets, commandLintTarget{Name: commandNameForPath(arg), Path: arg, Content: content})
+			continue
+		}
+
+		if commandFile, err := config.FindCommandFile(arg); err == nil && commandFile != nil {
+			content, err := os.ReadFile(commandFile.Path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", commandFile.Path, err)
+			}
+			targets = append(targets, commandLintTarget{Name: arg, Path: commandFile.Path, Content: content})
+			continue
+		}
+
+		template, err := assets.FindTemplate("command", arg)
+		if err != nil {
+			return nil, fmt.Errorf("no installed command, command template or file named '%s'", arg)
+		}
+		content, err := template.ReadFile()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		_, name := assets.SplitQualifiedName(arg)
+		targets = append(targets, commandLintTarget{Name: name, Path: template.Location(), Content: content})
+	}
+	return targets, nil
+}
+
+// commandLintDir returns every command file under a commands directory
+func commandLintDir(dir string) ([]commandLintTarget, error) {
+	var targets []commandLintTarget
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(file) != ".md" {
+			return nil
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, commandLintTarget{Name: types.CommandNameFromPath(filepath.ToSlash(rel)), Path: file, Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	return targets, nil
+}
+
+// commandNameForPath names a command file by its path below the nearest commands directory
+func commandNameForPath(file string) string {
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(file)), "/")
+	for i := len(parts) - 2; i >= 0; i-- {
+		if parts[i] == "commands" {
+			return types.CommandNameFromPath(strings.Join(parts[i+1:], "/"))
+		}
+	}
+	return types.CommandNameFromPath(parts[len(parts)-1])
+}
+
+// lintCommand checks one command; @file references are resolved against dir
+func lintCommand(name string, content []byte, dir string) []lintIssue {
+	var issues []lintIssue
+	warn := func(format string, args ...interface{}) {
+		issues = append(issues, lintIssue{Message: fmt.Sprintf(format, args...)})
+	}
+	fail := func(format string, args ...interface{}) {
+		issues = append(issues, lintIssue{Error: true, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if !isValidCommandName(name) {
+		warn("name '%s' should use lowercase letters, numbers and hyphens, with ':' between namespaces", name)
+	}
+	invocation := types.CommandInvocation(name)
+	if builtinSlashCommands[strings.TrimPrefix(invocation, "/")] {
+		warn("%s is a built-in Claude Code command, which takes precedence", invocation)
+	}
+
+	command, err := types.ParseCommand(name, string(content))
+	if err != nil {
+		fail("%v", err)
+		return issues
+	}
+	frontmatter := command.Frontmatter
+
+	if raw, _, err := types.SplitFrontmatter(string(content)); err == nil && strings.HasPrefix(string(content), "---") {
+		var values map[string]interface{}
+		if yaml.Unmarshal([]byte(raw), &values) == nil {
+			known := make(map[string]bool)
+			for _, key := range types.CommandFrontmatterKeys {
+				known[key] = true
+			}
+			var unknown []string
+			for key := range values {
+				if !known[key] {
+					unknown = append(unknown, key)
+				}
+			}
+			sort.Strings(unknown)
+			for _, key := range unknown {
+				warn("unknown frontmatter key '%s' is ignored (known: %s)", key, strings.Join(types.CommandFrontmatterKeys, ", "))
+			}
+		}
+	}
+
+	body := strings.TrimSpace(command.Body)
+	if body == "" {
+		fail("the prompt is empty")
+	}
+	if frontmatter.Description == "" {
+		warn("no description; the slash command menu shows the prompt's first line instead")
+	}
+
+	usesArguments := commandArgumentsPattern.MatchString(body)
+	if usesArguments && frontmatter.ArgumentHint == "" {
+		warn("the prompt uses arguments but there is no argument-hint to tell users what to pass")
+	} else if !usesArguments && frontmatter.ArgumentHint != "" {
+		warn("argument-hint is set but the prompt never uses $ARGUMENTS or $1..$9")
+	}
+
+	allowsBash := false
+	for _, tool := range frontmatter.AllowedTools {
+		toolName, _, _ := strings.Cut(tool, "(")
+		toolName = strings.TrimSpace(toolName)
+		if toolName == "Bash" {
+			allowsBash = true
+		}
+		if !types.KnownTools[toolName] && !strings.HasPrefix(toolName, "mcp__") {
+			warn("allowed-tools names unknown tool '%s'", tool)
+		}
+	}
+	if matches := commandBashPattern.FindAllStringSubmatch(body, -1); len(matches) > 0 && !allowsBash {
+		fail("the prompt runs !`%s` but allowed-tools doesn't include Bash", matches[0][1])
+	}
+
+	if model := frontmatter.Model; model != "" && model != "sonnet" && model != "opus" && model != "haiku" && !strings.HasPrefix(model, "claude-") {
+		warn("model '%s' is not a model alias (sonnet, opus, haiku) or a claude- model name", model)
+	}
+
+	seen := make(map[string]bool)
+	for _, match := range commandFileRefPattern.FindAllStringSubmatch(body, -1) {
+		ref := strings.TrimRight(match[1], ".")
+		if ref == "" || seen[ref] || strings.Contains(ref, "$") {
+			continue
+		}
+		seen[ref] = true
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(ref))); err != nil {
+			warn("@%s doesn't exist in this project", ref)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Error && !issues[j].Error })
+	return issues
+}
@@ -5,11 +5,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/expander"
+	"github.com/zxj777/claude-helper/pkg/types"
+	"gopkg.in/yaml.v3"
 )
 
 var configCmd = &cobra.Command{
@@ -21,14 +26,48 @@ var configCmd = &cobra.Command{
 var configTextExpanderCmd = &cobra.Command{
 	Use:   "text-expander",
 	Short: "Configure text expander mappings",
-	Long:  `Add, remove, or list text expander mappings.`,
+	Long: `Add, remove, or list text expander mappings.
+
+Mappings are merged from three config files, later ones overriding earlier ones:
+  user     ~/.config/claude-helper/text-expander.json  personal shortcuts for every project
+  team     .claude/config/text-expander.team.json      shared shortcuts committed with the project
+  project  .claude/config/text-expander.json           this project's own shortcuts
+
+Commands that change mappings use --scope to pick the file (project by default).`,
 }
 
 var addMappingCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add new text expander mappings",
-	Long:  `Interactively add new text expander mappings.`,
-	RunE:  addTextExpanderMappings,
+	Long: `Add new text expander mappings, interactively or from flags.
+
+With --marker and --text a single mapping is added. With --from mappings are
+read from a YAML or JSON file, either in the config file format (a "mappings"
+map and optional "commands") or as a plain map of marker to text. Without
+either, mappings are asked for interactively.
+
+Existing markers are only overwritten with --force. Mappings are added to
+the project config unless --scope says otherwise.
+
+Examples:
+  cchp config text-expander add --marker -t --text "write table-driven tests for {{1}}"
+  cchp config text-expander add --from team-snippets.yaml --scope team
+  cchp config text-expander add --marker -d --text "explain in detail" --scope user`,
+	RunE: addTextExpanderMappings,
+}
+
+var expandCmd = &cobra.Command{
+	Use:   "expand [prompt]",
+	Short: "Preview how a prompt is expanded",
+	Long: `Expand a prompt with the current mappings, as the text-expander hook would,
+and show which markers fired. The prompt is read from stdin when not given.
+
+Put -- before a prompt that starts with a marker such as -d.
+
+Examples:
+  cchp config text-expander expand -- "-t(src/foo.go) and -d"
+  echo "-d please" | cchp config text-expander expand`,
+	RunE: expandTextExpanderPrompt,
 }
 
 var listMappingsCmd = &cobra.Command{
@@ -41,9 +80,11 @@ var listMappingsCmd = &cobra.Command{
 var removeMappingCmd = &cobra.Command{
 	Use:   "remove <marker>",
 	Short: "Remove a text expander mapping",
-	Long:  `Remove a specific text expander mapping by its marker.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  removeTextExpanderMapping,
+	Long: `Remove a specific text expander mapping by its marker.
+
+Without --scope the mapping is removed from the config it currently comes from.`,
+	Args: cobra.ExactArgs(1),
+	RunE: removeTextExpanderMapping,
 }
 
 func init() {
@@ -52,19 +93,37 @@ func init() {
 	configTextExpanderCmd.AddCommand(addMappingCmd)
 	configTextExpanderCmd.AddCommand(listMappingsCmd)
 	configTextExpanderCmd.AddCommand(removeMappingCmd)
+	configTextExpanderCmd.AddCommand(expandCmd)
+
+	addMappingCmd.Flags().String("marker", "", "Marker to add (requires --text)")
+	addMappingCmd.Flags().String("text", "", "Replacement text for --marker")
+	addMappingCmd.Flags().String("from", "", "Add the mappings in a YAML or JSON file")
+	addMappingCmd.Flags().BoolP("force", "f", false, "Overwrite existing markers")
+	addMappingCmd.Flags().String("scope", textExpanderProject, "Config to add to: user, team or project")
+	removeMappingCmd.Flags().String("scope", "", "Config to remove from: user, team or project")
 }
 
 func addTextExpanderMappings(cmd *cobra.Command, args []string) error {
-	fmt.Println("🔧 Adding new Text Expander mappings...")
-	fmt.Println("Press Enter with empty marker to finish.")
-	fmt.Println()
+	marker, _ := cmd.Flags().GetString("marker")
+	text, _ := cmd.Flags().GetString("text")
+	from, _ := cmd.Flags().GetString("from")
+	force, _ := cmd.Flags().GetBool("force")
+	scope, _ := cmd.Flags().GetString("scope")
 
 	// Get config path
-	configPath, err := getTextExpanderConfigPath()
+	configPath, err := getTextExpanderConfigPath(scope)
 	if err != nil {
 		return err
 	}
 
+	if marker != "" || text != "" || from != "" {
+		return addTextExpanderMappingsFromFlags(configPath, marker, text, from, force)
+	}
+
+	fmt.Println("🔧 Adding new Text Expander mappings...")
+	fmt.Println("Press Enter with empty marker to finish.")
+	fmt.Println()
+
 	// Load existing config
 	textConfig, err := loadTextExpanderConfig(configPath)
 	if err != nil {
@@ -87,7 +146,7 @@ func addTextExpanderMappings(cmd *cobra.Command, args []string) error {
 			break
 		}
 
-		if !isValidMarker(marker) {
+		if !expander.IsValidMarker(marker) {
 			fmt.Println("❌ Invalid marker. Use format like: -d, -v, --explain, debug")
 			continue
 		}
@@ -132,10 +191,35 @@ func addTextExpanderMappings(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func listTextExpanderMappings(cmd *cobra.Command, args []string) error {
-	configPath, err := getTextExpanderConfigPath()
-	if err != nil {
-		return err
+// addTextExpanderMappingsFromFlags adds the mapping given with --marker/--text
+// or the mappings in the --from file
+func addTextExpanderMappingsFromFlags(configPath, marker, text, from string, force bool) error {
+	if from != "" && (marker != "" || text != "") {
+		return fmt.Errorf("use either --from or --marker and --text")
+	}
+
+	incoming := &types.TextExpanderConfig{Mappings: map[string]string{}}
+	if from != "" {
+		var err error
+		if incoming, err = readMappingsFile(from); err != nil {
+			return err
+		}
+	} else {
+		if marker == "" || strings.TrimSpace(text) == "" {
+			return fmt.Errorf("--marker and --text are both required")
+		}
+		incoming.Mappings[marker] = text
+	}
+
+	var invalid []string
+	for m := range incoming.Mappings {
+		if !expander.IsValidMarker(m) {
+			invalid = append(invalid, m)
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return fmt.Errorf("invalid marker(s): %s. Use format like: -d, -v, --explain, debug", strings.Join(invalid, ", "))
 	}
 
 	textConfig, err := loadTextExpanderConfig(configPath)
@@ -143,25 +227,241 @@ func listTextExpanderMappings(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if existing, exists := textConfig.Mappings[marker]; marker != "" && exists && existing != text && !force {
+		return fmt.Errorf("marker '%s' already exists with value: '%s'. Use --force to overwrite it", marker, existing)
+	}
+
+	added, _ := mergeMappings(textConfig, incoming, force)
+	commandsAdded, _ := mergeCommands(textConfig, incoming.Commands, force)
+
+	if added > 0 || commandsAdded > 0 {
+		if err := saveTextExpanderConfig(configPath, textConfig); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+	if added > 0 {
+		fmt.Printf("💾 Added %d new mappings\n", added)
+	} else {
+		fmt.Println("No new mappings added")
+	}
+	return nil
+}
+
+// mergeMappings adds incoming mappings to textConfig, keeping existing ones that
+// differ unless force is set. It returns how many mappings were added and skipped.
+func mergeMappings(textConfig, incoming *types.TextExpanderConfig, force bool) (int, int) {
+	markers := make([]string, 0, len(incoming.Mappings))
+	for m := range incoming.Mappings {
+		markers = append(markers, m)
+	}
+	sort.Strings(markers)
+
+	added, skipped := 0, 0
+	for _, m := range markers {
+		replacement := incoming.Mappings[m]
+		if existing, exists := textConfig.Mappings[m]; exists {
+			if existing == replacement {
+				continue
+			}
+			if !force {
+				fmt.Printf("⚠️  Marker '%s' already exists with value: '%s' (use --force to overwrite)\n", m, existing)
+				skipped++
+				continue
+			}
+		}
+		textConfig.Mappings[m] = replacement
+		fmt.Printf("✅ Added mapping: '%s' → '%s'\n", m, replacement)
+		added++
+	}
+	return added, skipped
+}
+
+// mergeCommands adds commands for {{cmd:name}} to textConfig, keeping existing
+// ones that differ unless force is set. It returns how many were added and skipped.
+func mergeCommands(textConfig *types.TextExpanderConfig, commands map[string]string, force bool) (int, int) {
+	added, skipped := 0, 0
+	for _, name := range sortedKeys(commands) {
+		command := commands[name]
+		if existing, exists := textConfig.Commands[name]; exists {
+			if existing == command {
+				continue
+			}
+			if !force {
+				fmt.Printf("⚠️  Command '%s' already exists: '%s' (use --force to overwrite)\n", name, existing)
+				skipped++
+				continue
+			}
+		}
+		if textConfig.Commands == nil {
+			textConfig.Commands = make(map[string]string)
+		}
+		textConfig.Commands[name] = command
+		added++
+	}
+	return added, skipped
+}
+
+// readMappingsFile reads mappings from a YAML or JSON file in the config file
+// format or as a plain map of marker to text
+func readMappingsFile(path string) (*types.TextExpanderConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var textConfig types.TextExpanderConfig
+	if err := yaml.Unmarshal(data, &textConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(textConfig.Mappings) == 0 {
+		var plain map[string]string
+		if err := yaml.Unmarshal(data, &plain); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: expected a \"mappings\" map or a map of marker to text", path)
+		}
+		textConfig.Mappings = plain
+	}
+	if len(textConfig.Mappings) == 0 && len(textConfig.Commands) == 0 {
+		return nil, fmt.Errorf("no mappings found in %s", path)
+	}
+	if textConfig.Mappings == nil {
+		textConfig.Mappings = make(map[string]string)
+	}
+	return &textConfig, nil
+}
+
+func expandTextExpanderPrompt(cmd *cobra.Command, args []string) error {
+	prompt := strings.Join(args, " ")
+	if len(args) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt: %w", err)
+		}
+		prompt = strings.TrimRight(string(data), "\n")
+	}
+
+	textConfig, _, err := loadMergedTextExpanderConfig()
+	if err != nil {
+		return err
+	}
+	e, err := newTextExpander(textConfig)
+	if err != nil {
+		return err
+	}
+
+	expanded, matches := e.Expand(prompt)
+	fmt.Println(expanded)
+	fmt.Println()
+
+	if len(matches) == 0 {
+		fmt.Println("No markers fired.")
+		return nil
+	}
+	defer printTextExpanderHookOutput(textConfig, expanded, matches)
+	fmt.Printf("Markers fired (%d):\n", len(matches))
+	for _, match := range matches {
+		line := fmt.Sprintf("  %s at %d", match.Marker, match.Offset)
+		if len(match.Args) > 0 {
+			line += fmt.Sprintf(" (args: %s)", strings.Join(match.Args, ", "))
+		}
+		fmt.Println(line)
+		if match.Err != nil {
+			for _, problem := range strings.Split(match.Err.Error(), "\n") {
+				fmt.Printf("    ⚠️  %s\n", problem)
+			}
+		}
+	}
+	return nil
+}
+
+// printTextExpanderHookOutput shows what the hook would send to Claude Code
+func printTextExpanderHookOutput(textConfig *types.TextExpanderConfig, expanded string, matches []expander.Match) {
+	output, mode, err := textExpanderHookOutput(textConfig, expanded, matches)
+	if err != nil {
+		fmt.Printf("\n⚠️  %v\n", err)
+	}
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return
+	}
+	fmt.Printf("\nHook output (%s mode):\n%s\n", mode, data)
+}
+
+// newTextExpander builds the expander the text-expander hook uses, with dynamic
+// values resolved in the current project
+func newTextExpander(textConfig *types.TextExpanderConfig) (*expander.Expander, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	values := &expander.Values{Dir: wd, Commands: textConfig.Commands}
+	return expander.New(textConfig.Mappings, textConfig.EscapeChar).WithResolver(values), nil
+}
+
+func listTextExpanderMappings(cmd *cobra.Command, args []string) error {
+	layers, err := textExpanderLayers()
+	if err != nil {
+		return err
+	}
+	textConfig, origins, err := loadMergedTextExpanderConfig()
+	if err != nil {
+		return err
+	}
+
 	if len(textConfig.Mappings) == 0 {
 		fmt.Println("No text expander mappings configured.")
-		fmt.Println("Use 'claude-helper config text-expander add' to add mappings.")
+		fmt.Println("Use 'cchp config text-expander add' to add mappings.")
 		return nil
 	}
 
+	markers := make([]string, 0, len(textConfig.Mappings))
+	for marker := range textConfig.Mappings {
+		markers = append(markers, marker)
+	}
+	sort.Strings(markers)
+
 	fmt.Printf("📝 Text Expander Mappings (%d total):\n\n", len(textConfig.Mappings))
-	for marker, replacement := range textConfig.Mappings {
-		fmt.Printf("  %s → %s\n", marker, replacement)
+	for _, marker := range markers {
+		fmt.Printf("  %s → %s  [%s]\n", marker, textConfig.Mappings[marker], origins[marker])
+	}
+	if len(textConfig.Commands) > 0 {
+		names := make([]string, 0, len(textConfig.Commands))
+		for name := range textConfig.Commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("\n⚙️  Commands available to {{cmd:name}}:\n")
+		for _, name := range names {
+			fmt.Printf("  %s: %s\n", name, textConfig.Commands[name])
+		}
+	}
+
+	fmt.Println("\nConfig files (later ones override earlier ones):")
+	for _, layer := range layers {
+		status := ""
+		if _, err := os.Stat(layer.Path); os.IsNotExist(err) {
+			status = " (not present)"
+		}
+		fmt.Printf("  %-8s %s%s\n", layer.Scope, layer.Path, status)
 	}
-	fmt.Printf("\nConfig file: %s\n", configPath)
 
 	return nil
 }
 
 func removeTextExpanderMapping(cmd *cobra.Command, args []string) error {
 	marker := args[0]
+	scope, _ := cmd.Flags().GetString("scope")
+
+	if scope == "" {
+		_, origins, err := loadMergedTextExpanderConfig()
+		if err != nil {
+			return err
+		}
+		if scope = origins[marker]; scope == "" {
+			return fmt.Errorf("mapping for marker '%s' not found", marker)
+		}
+	}
 
-	configPath, err := getTextExpanderConfigPath()
+	configPath, err := getTextExpanderConfigPath(scope)
 	if err != nil {
 		return err
 	}
@@ -172,7 +472,7 @@ func removeTextExpanderMapping(cmd *cobra.Command, args []string) error {
 	}
 
 	if _, exists := textConfig.Mappings[marker]; !exists {
-		return fmt.Errorf("mapping for marker '%s' not found", marker)
+		return fmt.Errorf("mapping for marker '%s' not found in the %s config", marker, scope)
 	}
 
 	delete(textConfig.Mappings, marker)
@@ -181,21 +481,112 @@ func removeTextExpanderMapping(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("✅ Removed mapping for marker: '%s'\n", marker)
+	fmt.Printf("✅ Removed mapping for marker: '%s' from the %s config\n", marker, scope)
+	if _, origins, err := loadMergedTextExpanderConfig(); err == nil && origins[marker] != "" {
+		fmt.Printf("⚠️  '%s' is still defined in the %s config\n", marker, origins[marker])
+	}
 	return nil
 }
 
-func getTextExpanderConfigPath() (string, error) {
-	// Use project-local config path instead of home directory
+// Text expander config scopes, lowest precedence first
+const (
+	textExpanderUser    = "user"
+	textExpanderTeam    = "team"
+	textExpanderProject = "project"
+)
+
+// textExpanderLayer is one of the config files merged for the text expander
+type textExpanderLayer struct {
+	Scope string
+	Path  string
+}
+
+// textExpanderLayers returns the text expander config files, lowest precedence first
+func textExpanderLayers() ([]textExpanderLayer, error) {
+	var layers []textExpanderLayer
+	for _, scope := range []string{textExpanderUser, textExpanderTeam, textExpanderProject} {
+		path, err := getTextExpanderConfigPath(scope)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, textExpanderLayer{Scope: scope, Path: path})
+	}
+	return layers, nil
+}
+
+// loadMergedTextExpanderConfig merges the text expander config layers. It also
+// returns the scope each mapping comes from.
+func loadMergedTextExpanderConfig() (*types.TextExpanderConfig, map[string]string, error) {
+	layers, err := textExpanderLayers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged := &types.TextExpanderConfig{
+		Mappings: make(map[string]string),
+		Commands: make(map[string]string),
+	}
+	origins := make(map[string]string)
+	for _, layer := range layers {
+		textConfig, err := loadTextExpanderConfig(layer.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load %s config: %w", layer.Scope, err)
+		}
+		for marker, replacement := range textConfig.Mappings {
+			merged.Mappings[marker] = replacement
+			origins[marker] = layer.Scope
+		}
+		for name, command := range textConfig.Commands {
+			merged.Commands[name] = command
+		}
+		if textConfig.EscapeChar != "" {
+			merged.EscapeChar = textConfig.EscapeChar
+		}
+		for marker, mode := range textConfig.Modes {
+			if merged.Modes == nil {
+				merged.Modes = make(map[string]string)
+			}
+			merged.Modes[marker] = mode
+		}
+		for _, field := range []struct{ from, to *string }{
+			{&textConfig.Mode, &merged.Mode},
+			{&textConfig.ContextTemplate, &merged.ContextTemplate},
+			{&textConfig.BlockTemplate, &merged.BlockTemplate},
+		} {
+			if *field.from != "" {
+				*field.to = *field.from
+			}
+		}
+	}
+	return merged, origins, nil
+}
+
+// getTextExpanderConfigPath returns the text expander config file for a scope
+func getTextExpanderConfigPath(scope string) (string, error) {
+	if scope == textExpanderUser {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		return filepath.Join(homeDir, ".config", "claude-helper", "text-expander.json"), nil
+	}
+
 	wd, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("failed to get working directory: %w", err)
 	}
-	return filepath.Join(wd, ".claude", "config", "text-expander.json"), nil
+	switch scope {
+	case textExpanderTeam:
+		return filepath.Join(wd, ".claude", "config", "text-expander.team.json"), nil
+	case textExpanderProject:
+		return filepath.Join(wd, ".claude", "config", "text-expander.json"), nil
+	default:
+		return "", fmt.Errorf("invalid scope '%s' (use user, team or project)", scope)
+	}
 }
 
-func loadTextExpanderConfig(configPath string) (*TextExpanderConfig, error) {
-	var textConfig TextExpanderConfig
+func loadTextExpanderConfig(configPath string) (*types.TextExpanderConfig, error) {
+	var textConfig types.TextExpanderConfig
 
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		// Config file doesn't exist, return empty config
@@ -215,43 +606,12 @@ func loadTextExpanderConfig(configPath string) (*TextExpanderConfig, error) {
 	if textConfig.Mappings == nil {
 		textConfig.Mappings = make(map[string]string)
 	}
-	
-	// Set default escape character if not specified
-	if textConfig.EscapeChar == "" {
-		textConfig.EscapeChar = "\\"
-	}
 
+	// An empty escape_char means the default backslash, and lets a lower config layer set it
 	return &textConfig, nil
 }
 
-// TextExpanderConfig represents the configuration for text expander
-type TextExpanderConfig struct {
-	Mappings    map[string]string `json:"mappings"`
-	EscapeChar  string           `json:"escape_char,omitempty"`  // Default: "\"
-}
-
-func isValidMarker(marker string) bool {
-	if len(marker) == 0 {
-		return false
-	}
-	
-	// Allow markers starting with - or -- or just alphanumeric words
-	if strings.HasPrefix(marker, "-") {
-		return len(marker) > 1
-	}
-	
-	// Allow simple word markers
-	for _, char := range marker {
-		if !((char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || 
-			 (char >= '0' && char <= '9') || char == '_' || char == '-') {
-			return false
-		}
-	}
-	
-	return true
-}
-
-func saveTextExpanderConfig(configPath string, textConfig *TextExpanderConfig) error {
+func saveTextExpanderConfig(configPath string, textConfig *types.TextExpanderConfig) error {
 	// Create config directory if it doesn't exist
 	configDir := filepath.Dir(configPath)
 	if err := os.MkdirAll(configDir, 0755); err != nil {
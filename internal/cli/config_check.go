@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/expander"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+var checkMappingsCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check text expander mappings for conflicts",
+	Long: `Check the merged text expander mappings for problems:
+
+  - markers that overlap (prefixes, case or dash variants of each other)
+  - markers defined in several config files, where one shadows another
+  - markers that are common words or command line flags
+  - markers that fired in recent prompts, from this project's Claude Code
+    transcripts, especially inside code where they were unlikely to be meant
+  - expansions referencing {{...}} markers, placeholders or commands that
+    don't exist
+
+Fails when an expansion can't work as written.`,
+	RunE: checkTextExpanderMappings,
+}
+
+func init() {
+	configTextExpanderCmd.AddCommand(checkMappingsCmd)
+	checkMappingsCmd.Flags().Int("transcripts", 10, "Number of recent transcripts to scan for prompts (0 to skip)")
+}
+
+// promptHit records a marker firing in a recent prompt
+type promptHit struct {
+	Prompts int
+	InCode  int
+	Example string
+}
+
+func checkTextExpanderMappings(cmd *cobra.Command, args []string) error {
+	transcripts, _ := cmd.Flags().GetInt("transcripts")
+
+	textConfig, _, err := loadMergedTextExpanderConfig()
+	if err != nil {
+		return err
+	}
+	if len(textConfig.Mappings) == 0 {
+		fmt.Println("No text expander mappings configured.")
+		return nil
+	}
+
+	fmt.Printf("🔍 Checking %d text expander mappings...\n", len(textConfig.Mappings))
+	warnings, failures := 0, 0
+	report := func(isError bool, message string) {
+		if isError {
+			failures++
+			fmt.Printf("  ❌ %s\n", message)
+		} else {
+			warnings++
+			fmt.Printf("  ⚠️  %s\n", message)
+		}
+	}
+
+	issues := expander.Check(textConfig.Mappings, textConfig.Commands)
+	sections := []struct {
+		title string
+		kinds map[string]bool
+	}{
+		{"Overlapping markers", map[string]bool{expander.IssueOverlap: true}},
+		{"Common words and command line flags", map[string]bool{expander.IssueCommonWord: true, expander.IssueCLIFlag: true}},
+		{"References in expansions", map[string]bool{expander.IssueNestedMarker: true, expander.IssueUndefinedRef: true, expander.IssueUnknownCommand: true}},
+	}
+	for _, section := range sections {
+		printed := false
+		for _, issue := range issues {
+			if !section.kinds[issue.Kind] {
+				continue
+			}
+			if !printed {
+				fmt.Printf("\n%s:\n", section.title)
+				printed = true
+			}
+			report(issue.Error, issue.Message)
+		}
+	}
+
+	if _, _, err := textExpanderHookOutput(textConfig, "", modeMatches(textConfig)); err != nil {
+		fmt.Println("\nModes:")
+		report(true, err.Error())
+	}
+
+	shadowed, err := shadowedMappings()
+	if err != nil {
+		return err
+	}
+	if len(shadowed) > 0 {
+		fmt.Println("\nShadowed mappings:")
+		for _, message := range shadowed {
+			report(false, message)
+		}
+	}
+
+	if transcripts > 0 {
+		prompts, files, err := recentPrompts(transcripts)
+		if err != nil {
+			fmt.Printf("\n⚠️  Unable to read recent prompts: %v\n", err)
+		} else if len(prompts) > 0 {
+			hits := markerHits(expander.New(textConfig.Mappings, textConfig.EscapeChar), prompts)
+			markers := make([]string, 0, len(hits))
+			for marker := range hits {
+				markers = append(markers, marker)
+			}
+			sort.Strings(markers)
+
+			fmt.Printf("\nRecent prompts (%d prompts in %d transcripts):\n", len(prompts), files)
+			if len(markers) == 0 {
+				fmt.Println("  No markers fired.")
+			}
+			for _, marker := range markers {
+				hit := hits[marker]
+				if hit.InCode > 0 {
+					report(false, fmt.Sprintf("%s fired in %d prompt(s), %d time(s) inside code, e.g. %q", marker, hit.Prompts, hit.InCode, hit.Example))
+				} else {
+					fmt.Printf("  %s fired in %d prompt(s), e.g. %q\n", marker, hit.Prompts, hit.Example)
+				}
+			}
+		}
+	}
+
+	fmt.Println()
+	if failures == 0 && warnings == 0 {
+		fmt.Println("✓ No problems found")
+		return nil
+	}
+	fmt.Printf("Found %d error(s) and %d warning(s)\n", failures, warnings)
+	if failures > 0 {
+		return fmt.Errorf("%d mapping(s) can't work as written", failures)
+	}
+	return nil
+}
+
+// modeMatches pretends every marker with its own mode fired, so all modes get checked
+func modeMatches(textConfig *types.TextExpanderConfig) []expander.Match {
+	var matches []expander.Match
+	for marker := range textConfig.Modes {
+		matches = append(matches, expander.Match{Marker: marker})
+	}
+	return matches
+}
+
+// shadowedMappings describes markers that a later config file overrides with a different text
+func shadowedMappings() ([]string, error) {
+	layers, err := textExpanderLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	defined := make(map[string][]string) // marker -> scopes, lowest precedence first
+	texts := make(map[string]map[string]string)
+	for _, layer := range layers {
+		textConfig, err := loadTextExpanderConfig(layer.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s config: %w", layer.Scope, err)
+		}
+		for marker, text := range textConfig.Mappings {
+			defined[marker] = append(defined[marker], layer.Scope)
+			if texts[marker] == nil {
+				texts[marker] = make(map[string]string)
+			}
+			texts[marker][layer.Scope] = text
+		}
+	}
+
+	var messages []string
+	for marker, scopes := range defined {
+		if len(scopes) < 2 {
+			continue
+		}
+		winner := scopes[len(scopes)-1]
+		for _, scope := range scopes[:len(scopes)-1] {
+			if texts[marker][scope] != texts[marker][winner] {
+				messages = append(messages, fmt.Sprintf("%s from the %s config overrides the %s config", marker, winner, scope))
+			}
+		}
+	}
+	sort.Strings(messages)
+	return messages, nil
+}
+
+var transcriptDirChars = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+// recentPrompts reads the user prompts from the project's most recent Claude Code transcripts
+func recentPrompts(limit int) ([]string, int, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	dir := filepath.Join(homeDir, ".claude", "projects", transcriptDirChars.ReplaceAllString(wd, "-"))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, 0, err
+	}
+	modTimes := make(map[string]int64)
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime().UnixNano()
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return modTimes[files[i]] > modTimes[files[j]] })
+	if len(files) > limit {
+		files = files[:limit]
+	}
+
+	var prompts []string
+	for _, file := range files {
+		filePrompts, err := transcriptPrompts(file)
+		if err != nil {
+			return nil, 0, err
+		}
+		prompts = append(prompts, filePrompts...)
+	}
+	return prompts, len(files), nil
+}
+
+// transcriptPrompts returns the prompts typed by the user in one transcript
+func transcriptPrompts(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	defer f.Close()
+
+	var prompts []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			Type    string `json:"type"`
+			IsMeta  bool   `json:"isMeta"`
+			Message struct {
+				Role    string          `json:"role"`
+				Content json.RawMessage `json:"content"`
+			} `json:"message"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.Type != "user" || entry.IsMeta || entry.Message.Role != "user" {
+			continue
+		}
+
+		var text string
+		if json.Unmarshal(entry.Message.Content, &text) != nil {
+			// Content blocks; tool results are not typed by the user
+			var blocks []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			}
+			if json.Unmarshal(entry.Message.Content, &blocks) != nil {
+				continue
+			}
+			var parts []string
+			for _, block := range blocks {
+				if block.Type == "text" {
+					parts = append(parts, block.Text)
+				}
+			}
+			text = strings.Join(parts, "\n")
+		}
+		if text != "" {
+			prompts = append(prompts, text)
+		}
+	}
+	return prompts, scanner.Err()
+}
+
+// markerHits counts the prompts each marker fires in, noting hits inside backtick code
+func markerHits(e *expander.Expander, prompts []string) map[string]*promptHit {
+	hits := make(map[string]*promptHit)
+	for _, prompt := range prompts {
+		_, matches := e.Expand(prompt)
+		seen := make(map[string]bool)
+		for _, match := range matches {
+			hit := hits[match.Marker]
+			if hit == nil {
+				hit = &promptHit{}
+				hits[match.Marker] = hit
+			}
+			inCode := strings.Count(prompt[:match.Offset], "`")%2 == 1
+			if inCode {
+				hit.InCode++
+			}
+			if !seen[match.Marker] {
+				hit.Prompts++
+				seen[match.Marker] = true
+			}
+			if hit.Example == "" || inCode && hit.InCode == 1 {
+				hit.Example = promptExcerpt(prompt, match.Offset, len(match.Marker))
+			}
+		}
+	}
+	return hits
+}
+
+// promptExcerpt returns the text around a match on one line
+func promptExcerpt(prompt string, offset, length int) string {
+	start, end := offset-30, offset+length+30
+	if start < 0 {
+		start = 0
+	}
+	if end > len(prompt) {
+		end = len(prompt)
+	}
+	excerpt := strings.ToValidUTF8(prompt[start:end], "")
+	excerpt = strings.Join(strings.Fields(excerpt), " ")
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(prompt) {
+		excerpt += "…"
+	}
+	return excerpt
+}
@@ -0,0 +1,392 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/expander"
+	"github.com/zxj777/claude-helper/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Snippet file formats for import and export
+const (
+	snippetFormatEspanso = "espanso"
+	snippetFormatCSV     = "csv"
+	snippetFormatJSON    = "json"
+)
+
+var importMappingsCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import snippets from espanso, CSV or JSON",
+	Long: `Import snippets from another text expander into a text expander config.
+
+Formats:
+  espanso  an espanso match file (matches with trigger/triggers and replace)
+  csv      rows of marker,text; a header row such as "marker,text" is skipped
+  json     the text expander config format, or a map of marker to text
+
+The format is taken from the file extension unless --format is given (.yml and
+.yaml files are read as espanso). Markers the text expander can't use, such as
+espanso's ":sig", are reported and skipped, as are markers that already have a
+different mapping unless --force is given.
+
+A json file may also list commands for {{cmd:name}}. They run on every prompt
+that uses them, so they are only listed unless --allow-commands is given.
+
+Examples:
+  cchp config text-expander import ~/.config/espanso/match/base.yml
+  cchp config text-expander import snippets.csv --scope user --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: importTextExpanderMappings,
+}
+
+var exportMappingsCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export snippets to espanso, CSV or JSON",
+	Long: `Export text expander mappings for another tool, or to share them.
+
+Without --scope the merged mappings of all config files are exported. Without
+a file they are written to stdout. The format is taken from the file extension
+unless --format is given, and is json for stdout.
+
+Examples:
+  cchp config text-expander export snippets.csv
+  cchp config text-expander export --format espanso --scope user > cchp.yml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: exportTextExpanderMappings,
+}
+
+func init() {
+	configTextExpanderCmd.AddCommand(importMappingsCmd)
+	configTextExpanderCmd.AddCommand(exportMappingsCmd)
+
+	importMappingsCmd.Flags().String("format", "", "File format: espanso, csv or json (default from the file extension)")
+	importMappingsCmd.Flags().String("scope", textExpanderProject, "Config to import into: user, team or project")
+	importMappingsCmd.Flags().BoolP("force", "f", false, "Overwrite existing markers")
+	importMappingsCmd.Flags().Bool("dry-run", false, "Show what would be imported without saving")
+	importMappingsCmd.Flags().Bool("allow-commands", false, "Also import the file's commands for {{cmd:name}}")
+
+	exportMappingsCmd.Flags().String("format", "", "File format: espanso, csv or json (default from the file extension)")
+	exportMappingsCmd.Flags().String("scope", "", "Export only this config: user, team or project")
+}
+
+// snippetEntry is one snippet read from an import file, in file order
+type snippetEntry struct {
+	Marker string
+	Text   string
+}
+
+// snippetFile is the content of an import file
+type snippetFile struct {
+	Entries  []snippetEntry
+	Commands map[string]string
+	Skipped  []string // Entries that can't be expressed as mappings
+}
+
+func importTextExpanderMappings(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	format, _ := cmd.Flags().GetString("format")
+	scope, _ := cmd.Flags().GetString("scope")
+	force, _ := cmd.Flags().GetBool("force")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	allowCommands, _ := cmd.Flags().GetBool("allow-commands")
+
+	if format == "" {
+		format = snippetFormatForPath(path)
+	}
+	configPath, err := getTextExpanderConfigPath(scope)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	file, err := decodeSnippets(data, format)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	fmt.Printf("📥 Importing %d snippet(s) from %s (%s) into the %s config\n", len(file.Entries), path, format, scope)
+	for _, skipped := range file.Skipped {
+		fmt.Printf("⏭️  Skipped %s\n", skipped)
+	}
+
+	incoming := &types.TextExpanderConfig{Mappings: make(map[string]string)}
+	var rejected, duplicates []string
+	for _, entry := range file.Entries {
+		if !expander.IsValidMarker(entry.Marker) {
+			rejected = append(rejected, entry.Marker)
+			continue
+		}
+		if existing, ok := incoming.Mappings[entry.Marker]; ok {
+			if existing != entry.Text {
+				duplicates = append(duplicates, entry.Marker)
+			}
+			continue
+		}
+		incoming.Mappings[entry.Marker] = entry.Text
+	}
+	if len(rejected) > 0 {
+		fmt.Printf("❌ Rejected %d invalid marker(s): %s\n", len(rejected), strings.Join(rejected, ", "))
+		fmt.Println("   Markers look like -d, --explain or debug (letters, digits, '_' and '-')")
+	}
+	if len(duplicates) > 0 {
+		fmt.Printf("⚠️  Defined more than once in %s, kept the first: %s\n", path, strings.Join(duplicates, ", "))
+	}
+
+	textConfig, err := loadTextExpanderConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	added, skipped := mergeMappings(textConfig, incoming, force)
+	if skipped > 0 {
+		fmt.Printf("⚠️  %d conflict(s) with existing mappings kept the existing value (use --force to overwrite)\n", skipped)
+	}
+
+	// {{cmd:name}} runs these commands, so a shared snippet file can't grant that silently
+	commandsAdded := 0
+	if len(file.Commands) > 0 {
+		fmt.Printf("⚙️  %s defines %d command(s) for {{cmd:name}}:\n", path, len(file.Commands))
+		for _, name := range sortedKeys(file.Commands) {
+			fmt.Printf("   %s: %s\n", name, file.Commands[name])
+		}
+		if allowCommands {
+			var commandsSkipped int
+			commandsAdded, commandsSkipped = mergeCommands(textConfig, file.Commands, force)
+			if commandsSkipped > 0 {
+				fmt.Printf("⚠️  %d existing command(s) kept (use --force to overwrite)\n", commandsSkipped)
+			}
+		} else {
+			fmt.Println("   Not imported. Review them and use --allow-commands to let {{cmd:name}} run them")
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("\nDry run: %d mapping(s) and %d command(s) would be added to %s\n", added, commandsAdded, configPath)
+		return nil
+	}
+	if added > 0 || commandsAdded > 0 {
+		if err := saveTextExpanderConfig(configPath, textConfig); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+	fmt.Printf("✓ Imported %d mapping(s)", added)
+	if commandsAdded > 0 {
+		fmt.Printf(" and %d command(s)", commandsAdded)
+	}
+	fmt.Println()
+	return nil
+}
+
+func exportTextExpanderMappings(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	scope, _ := cmd.Flags().GetString("scope")
+
+	output := ""
+	if len(args) > 0 {
+		output = args[0]
+	}
+	if format == "" {
+		format = snippetFormatJSON
+		if output != "" {
+			format = snippetFormatForPath(output)
+		}
+	}
+
+	var textConfig *types.TextExpanderConfig
+	if scope == "" {
+		merged, _, err := loadMergedTextExpanderConfig()
+		if err != nil {
+			return err
+		}
+		textConfig = merged
+	} else {
+		configPath, err := getTextExpanderConfigPath(scope)
+		if err != nil {
+			return err
+		}
+		if textConfig, err = loadTextExpanderConfig(configPath); err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+	}
+
+	data, err := encodeSnippets(textConfig, format)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Printf("✓ Exported %d mapping(s) to %s\n", len(textConfig.Mappings), output)
+	if len(textConfig.Commands) > 0 && format != snippetFormatJSON {
+		fmt.Printf("⚠️  %d command(s) for {{cmd:name}} can only be exported as json\n", len(textConfig.Commands))
+	}
+	return nil
+}
+
+// snippetFormatForPath returns the snippet format of a file from its extension
+func snippetFormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return snippetFormatCSV
+	case ".yml", ".yaml":
+		return snippetFormatEspanso
+	default:
+		return snippetFormatJSON
+	}
+}
+
+// espansoFile is the part of an espanso match file that maps to text expander snippets
+type espansoFile struct {
+	Matches []espansoMatch `yaml:"matches"`
+}
+
+type espansoMatch struct {
+	Trigger  string   `yaml:"trigger,omitempty"`
+	Triggers []string `yaml:"triggers,omitempty"`
+	Replace  string   `yaml:"replace"`
+}
+
+// csvHeaders are first-row cells treated as a header rather than a marker
+var csvHeaders = map[string]bool{"marker": true, "trigger": true, "abbreviation": true, "shortcut": true, "keyword": true}
+
+// decodeSnippets reads an import file in one of the snippet formats
+func decodeSnippets(data []byte, format string) (*snippetFile, error) {
+	file := &snippetFile{}
+
+	switch format {
+	case snippetFormatEspanso:
+		var espanso espansoFile
+		if err := yaml.Unmarshal(data, &espanso); err != nil {
+			return nil, err
+		}
+		for i, match := range espanso.Matches {
+			triggers := match.Triggers
+			if match.Trigger != "" {
+				triggers = append([]string{match.Trigger}, triggers...)
+			}
+			if len(triggers) == 0 || match.Replace == "" {
+				file.Skipped = append(file.Skipped, fmt.Sprintf("match %d: only trigger/replace matches can be imported", i+1))
+				continue
+			}
+			for _, trigger := range triggers {
+				file.Entries = append(file.Entries, snippetEntry{Marker: trigger, Text: match.Replace})
+			}
+		}
+
+	case snippetFormatCSV:
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		for row := 1; ; row++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+				continue
+			}
+			if row == 1 && csvHeaders[strings.ToLower(strings.TrimSpace(record[0]))] {
+				continue
+			}
+			if len(record) < 2 || strings.TrimSpace(record[0]) == "" || record[1] == "" {
+				file.Skipped = append(file.Skipped, fmt.Sprintf("row %d: expected a marker and its text", row))
+				continue
+			}
+			file.Entries = append(file.Entries, snippetEntry{Marker: strings.TrimSpace(record[0]), Text: record[1]})
+		}
+
+	case snippetFormatJSON:
+		var textConfig types.TextExpanderConfig
+		if err := json.Unmarshal(data, &textConfig); err != nil || len(textConfig.Mappings) == 0 {
+			var plain map[string]string
+			if err := json.Unmarshal(data, &plain); err != nil {
+				return nil, fmt.Errorf("expected a \"mappings\" map or a map of marker to text")
+			}
+			textConfig.Mappings = plain
+		}
+		for _, marker := range sortedKeys(textConfig.Mappings) {
+			file.Entries = append(file.Entries, snippetEntry{Marker: marker, Text: textConfig.Mappings[marker]})
+		}
+		file.Commands = textConfig.Commands
+
+	default:
+		return nil, fmt.Errorf("unknown format '%s' (use espanso, csv or json)", format)
+	}
+
+	return file, nil
+}
+
+// encodeSnippets writes mappings in one of the snippet formats
+func encodeSnippets(textConfig *types.TextExpanderConfig, format string) ([]byte, error) {
+	markers := sortedKeys(textConfig.Mappings)
+	var buf bytes.Buffer
+
+	switch format {
+	case snippetFormatEspanso:
+		espanso := espansoFile{Matches: []espansoMatch{}}
+		for _, marker := range markers {
+			espanso.Matches = append(espanso.Matches, espansoMatch{Trigger: marker, Replace: textConfig.Mappings[marker]})
+		}
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(espanso); err != nil {
+			return nil, fmt.Errorf("failed to encode espanso matches: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode espanso matches: %w", err)
+		}
+
+	case snippetFormatCSV:
+		writer := csv.NewWriter(&buf)
+		records := [][]string{{"marker", "text"}}
+		for _, marker := range markers {
+			records = append(records, []string{marker, textConfig.Mappings[marker]})
+		}
+		if err := writer.WriteAll(records); err != nil {
+			return nil, fmt.Errorf("failed to encode CSV: %w", err)
+		}
+
+	case snippetFormatJSON:
+		exported := types.TextExpanderConfig{Mappings: textConfig.Mappings, Commands: textConfig.Commands}
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(exported); err != nil {
+			return nil, fmt.Errorf("failed to encode JSON: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown format '%s' (use espanso, csv or json)", format)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -7,17 +7,26 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/assets"
 	"github.com/zxj777/claude-helper/pkg/types"
+	"gopkg.in/yaml.v3"
 )
 
 var createCmd = &cobra.Command{
 	Use:   "create <type> <name>",
 	Short: "Create a new component template",
-	Long: `Create a new agent or hook template in the assets/templates directory.
+	Long: `Create a new agent, hook or slash command template in a user template directory.
+
+Templates are written to the project template directory (.claude/templates)
+or, with --global, to ~/.config/claude-helper/templates. Templates in these
+directories are picked up by list, info and install, and shadow built-in
+templates with the same name.
 
 Examples:
-  claude-helper create agent my-reviewer
-  claude-helper create hook my-formatter`,
+  cchp create agent my-reviewer
+  cchp create hook my-formatter --global
+  cchp create hook guard-bash --event PreToolUse --matcher Bash --lang go
+  cchp create command frontend:component --argument-hint "<name>" --tools "Read,Write"`,
 	Args: cobra.ExactArgs(2),
 	RunE: createComponent,
 }
@@ -25,7 +34,13 @@ Examples:
 func init() {
 	rootCmd.AddCommand(createCmd)
 	createCmd.Flags().StringP("description", "d", "", "Component description")
-	createCmd.Flags().StringSliceP("tools", "t", []string{}, "Agent tools (comma-separated)")
+	createCmd.Flags().StringSliceP("tools", "t", []string{}, "Agent tools or command allowed-tools (comma-separated)")
+	createCmd.Flags().BoolP("global", "g", false, "Create the template in the user template directory instead of the project")
+	createCmd.Flags().String("event", string(types.PostToolUse), "Hook event (e.g. PreToolUse, PostToolUse, UserPromptSubmit, Stop)")
+	createCmd.Flags().String("matcher", "", "Hook matcher (defaults to a sensible matcher for the event)")
+	createCmd.Flags().String("lang", "python", "Hook script language: go, python, bash or node")
+	createCmd.Flags().String("argument-hint", "", "Command argument hint shown in the slash command menu, e.g. \"<file> [focus]\"")
+	createCmd.Flags().String("model", "", "Model the command runs with (defaults to the session's model)")
 }
 
 func createComponent(cmd *cobra.Command, args []string) error {
@@ -33,38 +48,66 @@ func createComponent(cmd *cobra.Command, args []string) error {
 	componentName := args[1]
 
 	// Validate component type
-	if componentType != "agent" && componentType != "hook" {
-		return fmt.Errorf("invalid component type '%s'. Must be 'agent' or 'hook'", componentType)
+	if componentType != "agent" && componentType != "hook" && componentType != "command" {
+		return fmt.Errorf("invalid component type '%s'. Must be 'agent', 'hook' or 'command'", componentType)
 	}
 
 	// Validate component name
-	if !isValidComponentName(componentName) {
+	if componentType == "command" {
+		if !isValidCommandName(componentName) {
+			return fmt.Errorf("invalid command name '%s'. Use lowercase letters, numbers, and hyphens, with ':' between namespaces", componentName)
+		}
+	} else if !isValidComponentName(componentName) {
 		return fmt.Errorf("invalid component name '%s'. Use lowercase letters, numbers, and hyphens only", componentName)
 	}
 
 	description, _ := cmd.Flags().GetString("description")
 	tools, _ := cmd.Flags().GetStringSlice("tools")
+	global, _ := cmd.Flags().GetBool("global")
+
+	templatesDir, err := getCreateTemplatesDir(global)
+	if err != nil {
+		return err
+	}
 
 	fmt.Printf("Creating %s template: %s\n", componentType, componentName)
 
 	switch componentType {
 	case "agent":
-		return createAgentTemplate(componentName, description, tools)
+		return createAgentTemplate(templatesDir, componentName, description, tools)
 	case "hook":
-		return createHookTemplate(componentName, description)
+		event, _ := cmd.Flags().GetString("event")
+		matcher, _ := cmd.Flags().GetString("matcher")
+		lang, _ := cmd.Flags().GetString("lang")
+		return createHookTemplate(templatesDir, componentName, description, hookScaffoldOptions{
+			Event:   types.HookEvent(event),
+			Matcher: matcher,
+			Lang:    lang,
+		})
+	case "command":
+		argumentHint, _ := cmd.Flags().GetString("argument-hint")
+		model, _ := cmd.Flags().GetString("model")
+		return createCommandTemplate(templatesDir, componentName, types.CommandFrontmatter{
+			Description:  description,
+			ArgumentHint: argumentHint,
+			AllowedTools: tools,
+			Model:        model,
+		})
 	default:
 		return fmt.Errorf("unsupported component type: %s", componentType)
 	}
 }
 
-func createAgentTemplate(name, description string, tools []string) error {
-	// Get templates directory
-	wd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
+// getCreateTemplatesDir returns the user template directory that create writes into
+func getCreateTemplatesDir(global bool) (string, error) {
+	if global {
+		return assets.GetUserTemplatesDir()
 	}
-	
-	agentsDir := filepath.Join(wd, "assets", "templates", "agents")
+	return assets.GetProjectTemplatesDir()
+}
+
+func createAgentTemplate(templatesDir, name, description string, tools []string) error {
+	agentsDir := filepath.Join(templatesDir, "agents")
 	if err := os.MkdirAll(agentsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create agents directory: %w", err)
 	}
@@ -102,40 +145,90 @@ func createAgentTemplate(name, description string, tools []string) error {
 	}
 
 	fmt.Printf("✓ Created agent template: %s\n", agentPath)
+	fmt.Printf("Install it with: cchp install %s\n", name)
 	return nil
 }
 
-func createHookTemplate(name, description string) error {
-	// Get templates directory
-	wd, err := os.Getwd()
+func createCommandTemplate(templatesDir, name string, frontmatter types.CommandFrontmatter) error {
+	commandPath := filepath.Join(templatesDir, "commands", filepath.FromSlash(types.CommandFilePath(name)))
+	if _, err := os.Stat(commandPath); err == nil {
+		return fmt.Errorf("command template '%s' already exists", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(commandPath), 0755); err != nil {
+		return fmt.Errorf("failed to create commands directory: %w", err)
+	}
+
+	if frontmatter.Description == "" {
+		frontmatter.Description = fmt.Sprintf("A custom slash command: %s", name)
+	}
+	command := types.Command{
+		Name:        name,
+		Frontmatter: frontmatter,
+		Body:        generateCommandPromptTemplate(frontmatter.ArgumentHint != ""),
+	}
+
+	content, err := command.ToMarkdown()
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
+		return err
 	}
-	
-	hooksDir := filepath.Join(wd, "assets", "templates", "hooks")
+	if err := os.WriteFile(commandPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write command template: %w", err)
+	}
+
+	fmt.Printf("✓ Created command template: %s\n", commandPath)
+	fmt.Printf("Install it with: cchp install %s\n", name)
+	fmt.Printf("Then run it in Claude Code with: %s\n", types.CommandInvocation(name))
+	return nil
+}
+
+// hookScaffoldOptions holds the create hook flags
+type hookScaffoldOptions struct {
+	Event   types.HookEvent
+	Matcher string
+	Lang    string
+}
+
+func createHookTemplate(templatesDir, name, description string, opts hookScaffoldOptions) error {
+	if !opts.Event.IsValid() {
+		return fmt.Errorf("invalid hook event '%s'", opts.Event)
+	}
+	language, ok := hookScaffoldLanguages[opts.Lang]
+	if !ok {
+		return fmt.Errorf("invalid hook language '%s'. Must be one of: go, python, bash, node", opts.Lang)
+	}
+
+	hooksDir := filepath.Join(templatesDir, "hooks")
 	if err := os.MkdirAll(hooksDir, 0755); err != nil {
 		return fmt.Errorf("failed to create hooks directory: %w", err)
 	}
 
 	hookPath := filepath.Join(hooksDir, name+".yaml")
-	
-	// Check if file already exists
+	scriptPath := filepath.Join(hooksDir, name+language.Ext)
+
+	// Check if files already exist
 	if _, err := os.Stat(hookPath); err == nil {
 		return fmt.Errorf("hook template '%s' already exists", name)
 	}
+	if _, err := os.Stat(scriptPath); err == nil {
+		return fmt.Errorf("hook script '%s' already exists", scriptPath)
+	}
 
 	// Set defaults if not provided
 	if description == "" {
 		description = fmt.Sprintf("A custom Claude hook: %s", name)
 	}
+	matcher := opts.Matcher
+	if matcher == "" {
+		matcher = hookEventSpecs[opts.Event].DefaultMatcher
+	}
 
 	// Create hook structure
 	hook := types.Hook{
 		Name:        name,
 		Description: description,
-		Event:       types.PostToolUse, // Default event
-		Matcher:     "Edit|Write",      // Default matcher
-		Command:     generateHookCommandTemplate(name),
+		Event:       opts.Event,
+		Matcher:     matcher,
+		Command:     fmt.Sprintf(language.Command, name),
 		Timeout:     30,
 		Enabled:     true,
 	}
@@ -146,12 +239,23 @@ func createHookTemplate(name, description string) error {
 		return fmt.Errorf("failed to generate hook YAML: %w", err)
 	}
 
-	// Write to file
+	script, err := generateHookScript(name, opts.Event, opts.Lang)
+	if err != nil {
+		return err
+	}
+
+	// Write to files
 	if err := os.WriteFile(hookPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write hook template: %w", err)
 	}
+	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		return fmt.Errorf("failed to write hook script: %w", err)
+	}
 
 	fmt.Printf("✓ Created hook template: %s\n", hookPath)
+	fmt.Printf("✓ Created %s hook script: %s\n", opts.Lang, scriptPath)
+	fmt.Printf("Install it with: cchp install %s\n", name)
+	fmt.Printf("Then try it with: cchp hook test %s\n", name)
 	return nil
 }
 
@@ -165,6 +269,30 @@ func isValidComponentName(name string) bool {
 	return len(name) > 0 && !strings.HasPrefix(name, "-") && !strings.HasSuffix(name, "-")
 }
 
+// isValidCommandName checks a command name, whose namespaces are joined with ':'
+func isValidCommandName(name string) bool {
+	for _, part := range strings.Split(name, types.CommandNamespaceSeparator) {
+		if !isValidComponentName(part) {
+			return false
+		}
+	}
+	return true
+}
+
+func generateCommandPromptTemplate(takesArguments bool) string {
+	prompt := `Describe the task Claude should carry out when this command runs.
+
+## Steps
+1. Gather the context the task needs
+2. Do the work
+3. Summarize what changed
+`
+	if takesArguments {
+		prompt += "\nArguments: $ARGUMENTS\n"
+	}
+	return prompt
+}
+
 func generateAgentPromptTemplate(name, description string) string {
 	return fmt.Sprintf(`You are %s, %s.
 
@@ -180,47 +308,10 @@ Describe your specific role and responsibilities here.
 Add specific examples of how you should behave or respond.`, strings.Title(strings.ReplaceAll(name, "-", " ")), description)
 }
 
-func generateHookCommandTemplate(name string) string {
-	return fmt.Sprintf(`#!/bin/bash
-# %s hook script
-echo "Executing %s hook on file: $1"
-
-# Add your custom logic here
-# Example:
-# if [[ "$1" == *.go ]]; then
-#   echo "Processing Go file: $1"
-# fi
-
-echo "Hook completed successfully"`, name, name)
-}
-
 func generateHookYAMLTemplate(hook types.Hook) (string, error) {
-	return fmt.Sprintf(`name: %s
-description: %s
-event: %s
-matcher: %s
-command: |
-%s
-timeout: %d
-enabled: %t`, 
-		hook.Name,
-		hook.Description,
-		hook.Event,
-		hook.Matcher,
-		indentString(hook.Command, "  "),
-		hook.Timeout,
-		hook.Enabled), nil
-}
-
-func indentString(s, indent string) string {
-	lines := strings.Split(s, "\n")
-	var indented []string
-	for _, line := range lines {
-		if line != "" {
-			indented = append(indented, indent+line)
-		} else {
-			indented = append(indented, "")
-		}
+	content, err := yaml.Marshal(hook)
+	if err != nil {
+		return "", err
 	}
-	return strings.Join(indented, "\n")
-}
\ No newline at end of file
+	return string(content), nil
+}
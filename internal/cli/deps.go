@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/internal/manifest"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+// runtimeFile is a shared file components can declare under requires.runtime
+type runtimeFile struct {
+	Files   []string // Paths relative to the project root
+	Install func() error
+}
+
+// runtimeFiles lists the shared runtime files cchp knows how to install
+var runtimeFiles = map[string]runtimeFile{
+	"run-python": {
+		Files:   []string{".claude/hooks/run-python.sh", ".claude/hooks/run-python.bat"},
+		Install: ensureCrossPlatformRunPythonScripts,
+	},
+}
+
+// dependency is a component found while resolving requirements
+type dependency struct {
+	Ref  string // Template reference as written in requires
+	Name string
+	Type string
+}
+
+// resolveInstallOrder walks the requires graph of a component and returns every
+// component to install, dependencies first, along with the runtime files they need
+func resolveInstallOrder(templateRef string) ([]dependency, []string, error) {
+	var order []dependency
+	runtimeSet := make(map[string]bool)
+	done := make(map[string]bool)
+	var stack []string
+
+	var visit func(ref string) error
+	visit = func(ref string) error {
+		_, name := assets.SplitQualifiedName(ref)
+		if done[name] {
+			return nil
+		}
+		for i, visiting := range stack {
+			if visiting == name {
+				cycle := append(append([]string{}, stack[i:]...), name)
+				return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+			}
+		}
+
+		template, err := findComponentTemplate(ref)
+		if err != nil {
+			if len(stack) > 0 {
+				return fmt.Errorf("'%s' requires '%s': %w", stack[len(stack)-1], ref, err)
+			}
+			return err
+		}
+
+		stack = append(stack, name)
+		componentManifest, err := manifest.Load(template)
+		if err != nil {
+			return err
+		}
+		if componentManifest != nil {
+			for _, runtime := range componentManifest.Requires.Runtime {
+				if _, ok := runtimeFiles[runtime]; !ok {
+					return fmt.Errorf("'%s' requires unknown runtime '%s'", name, runtime)
+				}
+				runtimeSet[runtime] = true
+			}
+			for _, required := range componentManifest.Requires.Components {
+				if err := visit(required); err != nil {
+					return err
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+
+		done[name] = true
+		order = append(order, dependency{Ref: ref, Name: name, Type: template.Type})
+		return nil
+	}
+
+	if err := visit(templateRef); err != nil {
+		return nil, nil, err
+	}
+
+	runtimes := make([]string, 0, len(runtimeSet))
+	for runtime := range runtimeSet {
+		runtimes = append(runtimes, runtime)
+	}
+	sort.Strings(runtimes)
+	return order, runtimes, nil
+}
+
+// installDependencies installs the runtime files and components a component requires
+func installDependencies(templateRef string, opts installOptions) error {
+	order, runtimes, err := resolveInstallOrder(templateRef)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureRuntimeFiles(runtimes); err != nil {
+		return err
+	}
+
+	// The last entry is the component itself
+	for _, dep := range order[:len(order)-1] {
+		if installed, err := isComponentInstalled(dep.Name, dep.Type, ""); err == nil && installed {
+			continue
+		}
+
+		fmt.Printf("Installing dependency: %s %s\n", dep.Type, dep.Name)
+		err := installComponentFromTemplate(dep.Ref, installOptions{
+			IgnoreCompat:     opts.IgnoreCompat,
+			SkipDependencies: true,
+			AllowUnverified:  opts.AllowUnverified,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to install dependency '%s': %w", dep.Name, err)
+		}
+	}
+	return nil
+}
+
+// ensureRuntimeFiles installs any runtime files that are missing from the project
+func ensureRuntimeFiles(names []string) error {
+	for _, name := range names {
+		runtime := runtimeFiles[name]
+		missing := false
+		for _, file := range runtime.Files {
+			path, err := config.ResolveLockedFile(file)
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stat(path); err != nil {
+				missing = true
+			}
+		}
+		if !missing {
+			continue
+		}
+		if err := runtime.Install(); err != nil {
+			return fmt.Errorf("failed to install runtime '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// findDependents returns the lock keys of the installed components whose
+// manifests require the component under key
+func findDependents(key string) ([]string, error) {
+	lock, err := config.LoadLockfile()
+	if err != nil {
+		return nil, err
+	}
+	componentType, name, _ := types.SplitLockKey(key)
+
+	var dependents []string
+	for _, other := range sortedLockedKeys(lock) {
+		if other == key {
+			continue
+		}
+		entry := lock.Components[other]
+		componentManifest, err := loadComponentManifest(entry.Type, entry.Template)
+		if err != nil || componentManifest == nil {
+			continue
+		}
+		for _, required := range componentManifest.Requires.Components {
+			if _, requiredName := assets.SplitQualifiedName(required); requiredName != name {
+				continue
+			}
+			// Requirements name no type; one of another type with the same name doesn't count
+			if template, err := findComponentTemplate(required); err == nil && template.Type != componentType {
+				continue
+			}
+			dependents = append(dependents, other)
+			break
+		}
+	}
+	return dependents, nil
+}
+
+// collectRemovals returns the lock keys of the components to remove so that
+// nothing left installed requires any of keys. Dependents not in keys are
+// returned in extra; the full list is ordered so that dependents are removed
+// before what they require.
+func collectRemovals(keys []string) (ordered []string, extra []string, err error) {
+	selected := make(map[string]bool)
+	for _, key := range keys {
+		selected[key] = true
+	}
+
+	dependentsOf := make(map[string][]string)
+	queue := append([]string{}, keys...)
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+
+		dependents, err := findDependents(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		dependentsOf[key] = dependents
+		for _, dependent := range dependents {
+			if !selected[dependent] {
+				selected[dependent] = true
+				extra = append(extra, dependent)
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	// Depth-first so each component comes after everything that requires it
+	visited := make(map[string]bool)
+	var visit func(key string)
+	visit = func(key string) {
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		for _, dependent := range dependentsOf[key] {
+			visit(dependent)
+		}
+		ordered = append(ordered, key)
+	}
+	for _, key := range append(append([]string{}, keys...), extra...) {
+		visit(key)
+	}
+
+	return ordered, extra, nil
+}
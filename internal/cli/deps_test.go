@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// useTempProject makes an empty temporary project the working directory, with
+// an empty home directory, for the rest of the test
+func useTempProject(t *testing.T) string {
+	t.Helper()
+
+	project := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return project
+}
+
+// writeProjectAgents creates project agent templates in a temporary project,
+// each requiring the listed components, and makes it the working directory
+func writeProjectAgents(t *testing.T, requires map[string][]string) {
+	t.Helper()
+
+	project := useTempProject(t)
+	dir := filepath.Join(project, ".claude", "templates", "agents")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, components := range requires {
+		agent := "---\nname: " + name + "\ndescription: test agent\n---\nTest agent.\n"
+		if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte(agent), 0644); err != nil {
+			t.Fatal(err)
+		}
+		manifest := "version: 1.0.0\n"
+		if len(components) > 0 {
+			manifest += "requires:\n  components: [" + strings.Join(components, ", ") + "]\n"
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".manifest.yaml"), []byte(manifest), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResolveInstallOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		requires map[string][]string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "no requirements",
+			requires: map[string][]string{"test-a": nil},
+			want:     []string{"test-a"},
+		},
+		{
+			name:     "dependencies first",
+			requires: map[string][]string{"test-a": {"test-b"}, "test-b": {"test-c"}, "test-c": nil},
+			want:     []string{"test-c", "test-b", "test-a"},
+		},
+		{
+			name:     "shared dependency installed once",
+			requires: map[string][]string{"test-a": {"test-b", "test-c"}, "test-b": {"test-c"}, "test-c": nil},
+			want:     []string{"test-c", "test-b", "test-a"},
+		},
+		{
+			name:     "self cycle",
+			requires: map[string][]string{"test-a": {"test-a"}},
+			wantErr:  "dependency cycle: test-a -> test-a",
+		},
+		{
+			name:     "indirect cycle",
+			requires: map[string][]string{"test-a": {"test-b"}, "test-b": {"test-c"}, "test-c": {"test-b"}},
+			wantErr:  "dependency cycle: test-b -> test-c -> test-b",
+		},
+		{
+			name:     "missing dependency",
+			requires: map[string][]string{"test-a": {"test-missing"}},
+			wantErr:  "'test-a' requires 'test-missing'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeProjectAgents(t, tt.requires)
+
+			order, _, err := resolveInstallOrder("test-a")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveInstallOrder() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveInstallOrder() error = %v", err)
+			}
+
+			var got []string
+			for _, dep := range order {
+				got = append(got, dep.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveInstallOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRejectedInstallSkipsDependencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    installOptions
+		wantErr string
+	}{
+		{name: "unknown variable", opts: installOptions{Params: map[string]string{"bogus": "x"}}, wantErr: "does not declare any variables"},
+		{name: "local scope", opts: installOptions{Scope: "local"}, wantErr: "local scope is only supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeProjectAgents(t, map[string][]string{"test-a": {"test-b"}, "test-b": nil})
+
+			err := installComponentFromTemplate("test-a", tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("installComponentFromTemplate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if _, err := os.Stat(filepath.Join(".claude", "agents", "test-b.md")); !os.IsNotExist(err) {
+				t.Errorf("dependency test-b was installed for a rejected install")
+			}
+		})
+	}
+}
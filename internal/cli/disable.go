@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/zxj777/claude-helper/internal/config"
@@ -11,7 +12,7 @@ import (
 var disableCmd = &cobra.Command{
 	Use:   "disable <component-name>",
 	Short: "Disable an enabled component",
-	Long:  `Disable an installed agent or hook without removing it completely.`,
+	Long:  `Disable an installed agent, hook or slash command without removing it completely.`,
 	Args:  cobra.ExactArgs(1),
 	RunE:  disableComponent,
 }
@@ -26,7 +27,7 @@ func disableComponent(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Disabling component: %s\n", componentName)
 
 	// Check what type of component this is and if it's installed
-	componentType, err := detectInstalledComponentType(componentName)
+	componentType, err := detectInstalledComponentType(componentName, "")
 	if err != nil {
 		return fmt.Errorf("component '%s' not found or not installed: %w", componentName, err)
 	}
@@ -36,6 +37,8 @@ func disableComponent(cmd *cobra.Command, args []string) error {
 		return disableAgent(componentName)
 	case "hook":
 		return disableHook(componentName)
+	case "command":
+		return disableCommand(componentName)
 	default:
 		return fmt.Errorf("unsupported component type: %s", componentType)
 	}
@@ -43,25 +46,47 @@ func disableComponent(cmd *cobra.Command, args []string) error {
 
 func disableAgent(name string) error {
 	// For agents, disabling means moving the file to a .disabled extension
-	agentsDir, err := config.GetAgentsPath()
+	agentFile, err := config.FindAgentFile(name)
 	if err != nil {
 		return err
 	}
+	if agentFile == nil {
+		return fmt.Errorf("agent file not found for '%s'", name)
+	}
+	return disableComponentFile("agent", name, agentFile)
+}
 
-	agentPath := fmt.Sprintf("%s/%s.md", agentsDir, name)
-	disabledPath := fmt.Sprintf("%s/%s.md.disabled", agentsDir, name)
+func disableCommand(name string) error {
+	// Claude Code only loads .md files, so commands are disabled the same way as agents
+	commandFile, err := config.FindCommandFile(name)
+	if err != nil {
+		return err
+	}
+	if commandFile == nil {
+		return fmt.Errorf("command file not found for '%s'", name)
+	}
+	return disableComponentFile("command", name, commandFile)
+}
+
+// disableComponentFile renames an agent or command file to .md.disabled
+func disableComponentFile(componentType, name string, file *config.ComponentFile) error {
+	label := strings.ToUpper(componentType[:1]) + componentType[1:]
+	if file.Disabled {
+		fmt.Printf("%s '%s' is already disabled (%s)\n", label, name, file.Path)
+		return nil
+	}
 
-	// Check if agent file exists
-	if _, err := os.Stat(agentPath); os.IsNotExist(err) {
-		return fmt.Errorf("agent file not found: %s", agentPath)
+	disabledPath := file.Path + ".disabled"
+	if _, err := os.Stat(disabledPath); err == nil {
+		return fmt.Errorf("a disabled copy already exists at %s; remove it before disabling again", disabledPath)
 	}
 
 	// Rename to .disabled
-	if err := os.Rename(agentPath, disabledPath); err != nil {
-		return fmt.Errorf("failed to disable agent: %w", err)
+	if err := os.Rename(file.Path, disabledPath); err != nil {
+		return fmt.Errorf("failed to disable %s: %w", componentType, err)
 	}
 
-	fmt.Printf("✓ Agent '%s' has been disabled\n", name)
+	fmt.Printf("✓ %s '%s' has been disabled (%s scope)\n", label, name, file.Scope)
 	return nil
 }
 
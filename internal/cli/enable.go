@@ -2,14 +2,17 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/config"
 )
 
 var enableCmd = &cobra.Command{
 	Use:   "enable <component-name>",
 	Short: "Enable a disabled component",
-	Long:  `Enable a previously installed but disabled agent or hook.`,
+	Long:  `Enable a previously installed but disabled agent, hook or slash command.`,
 	Args:  cobra.ExactArgs(1),
 	RunE:  enableComponent,
 }
@@ -24,24 +27,68 @@ func enableComponent(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Enabling component: %s\n", componentName)
 
 	// Check what type of component this is and if it's installed
-	componentType, err := detectInstalledComponentType(componentName)
+	componentType, err := detectInstalledComponentType(componentName, "")
 	if err != nil {
 		return fmt.Errorf("component '%s' not found or not installed: %w", componentName, err)
 	}
 
-	// For now, enabling/disabling is primarily relevant for hooks
-	// Agents are enabled/disabled by their presence in the filesystem
 	switch componentType {
 	case "agent":
-		fmt.Printf("Agent '%s' is controlled by file presence. It's already enabled if installed.\n", componentName)
-		return nil
+		return enableAgent(componentName)
 	case "hook":
 		return enableHook(componentName)
+	case "command":
+		return enableCommand(componentName)
 	default:
 		return fmt.Errorf("unsupported component type: %s", componentType)
 	}
 }
 
+func enableAgent(name string) error {
+	// Agents are disabled by renaming them to .md.disabled, so enabling renames them back
+	agentFile, err := config.FindAgentFile(name)
+	if err != nil {
+		return err
+	}
+	if agentFile == nil {
+		return fmt.Errorf("agent file not found for '%s'", name)
+	}
+	return enableComponentFile("agent", name, agentFile)
+}
+
+func enableCommand(name string) error {
+	// Commands are disabled the same way as agents
+	commandFile, err := config.FindCommandFile(name)
+	if err != nil {
+		return err
+	}
+	if commandFile == nil {
+		return fmt.Errorf("command file not found for '%s'", name)
+	}
+	return enableComponentFile("command", name, commandFile)
+}
+
+// enableComponentFile renames a disabled agent or command file back to .md
+func enableComponentFile(componentType, name string, file *config.ComponentFile) error {
+	label := strings.ToUpper(componentType[:1]) + componentType[1:]
+	if !file.Disabled {
+		fmt.Printf("%s '%s' is already enabled (%s)\n", label, name, file.Path)
+		return nil
+	}
+
+	enabledPath := strings.TrimSuffix(file.Path, ".disabled")
+	if _, err := os.Stat(enabledPath); err == nil {
+		return fmt.Errorf("an enabled copy already exists at %s; remove it before enabling", enabledPath)
+	}
+
+	if err := os.Rename(file.Path, enabledPath); err != nil {
+		return fmt.Errorf("failed to enable %s: %w", componentType, err)
+	}
+
+	fmt.Printf("✓ %s '%s' has been enabled (%s scope)\n", label, name, file.Scope)
+	return nil
+}
+
 func enableHook(name string) error {
 	// TODO: Implement hook enabling in settings.json
 	// This would require modifying the hook's "enabled" status or re-adding it
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Work with hooks",
+	Long:  `Commands for developing and debugging hooks.`,
+}
+
+var hookTestCmd = &cobra.Command{
+	Use:   "test <hook-name>",
+	Short: "Run a hook with a sample payload",
+	Long: `Run a hook's command the way Claude Code would, feeding it a sample payload
+for its event on stdin, and check that its output is a valid hook decision.
+
+The hook must be installed so that the scripts its command refers to exist in
+.claude/hooks. Use --payload to supply your own JSON payload.`,
+	Args: cobra.ExactArgs(1),
+	RunE: testHook,
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+	hookCmd.AddCommand(hookTestCmd)
+	hookTestCmd.Flags().String("payload", "", "Path to a JSON file used as the hook payload")
+}
+
+func testHook(cmd *cobra.Command, args []string) error {
+	hookName := args[0]
+	payloadFile, _ := cmd.Flags().GetString("payload")
+
+	template, err := findComponentTemplate(hookName)
+	if err != nil {
+		return fmt.Errorf("failed to find hook '%s': %w", hookName, err)
+	}
+	if template.Type != "hook" {
+		return fmt.Errorf("'%s' is an %s, not a hook", hookName, template.Type)
+	}
+
+	content, err := template.ReadFile()
+	if err != nil {
+		return fmt.Errorf("failed to read hook template: %w", err)
+	}
+	hook, err := parseHookTemplate(content, types.HookFormatForPath(template.Path))
+	if err != nil {
+		return fmt.Errorf("failed to parse hook template: %w", err)
+	}
+
+	if installed, err := config.IsHookInstalled(hookName); err == nil && !installed {
+		fmt.Printf("⚠️  Hook '%s' is not installed; scripts its command refers to may be missing\n", hookName)
+	}
+
+	var payload []byte
+	if payloadFile != "" {
+		payload, err = os.ReadFile(payloadFile)
+		if err != nil {
+			return fmt.Errorf("failed to read payload file: %w", err)
+		}
+		if !json.Valid(payload) {
+			return fmt.Errorf("payload file %s is not valid JSON", payloadFile)
+		}
+	} else {
+		payload, err = json.MarshalIndent(buildSampleHookPayload(hook.Event), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to build sample payload: %w", err)
+		}
+	}
+
+	command := hook.GetPlatformCommand()
+	fmt.Printf("Event:   %s\n", hook.Event)
+	fmt.Printf("Command: %s\n", command)
+	fmt.Printf("Payload:\n%s\n\n", payload)
+
+	result, err := runHookCommand(command, payload, time.Duration(hook.Timeout)*time.Second)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exit code: %d (%s)\n", result.ExitCode, describeHookExitCode(result.ExitCode))
+	if result.Stdout != "" {
+		fmt.Printf("Stdout:\n%s\n", strings.TrimRight(result.Stdout, "\n"))
+	}
+	if result.Stderr != "" {
+		fmt.Printf("Stderr:\n%s\n", strings.TrimRight(result.Stderr, "\n"))
+	}
+
+	if problems := validateHookOutput(hook.Event, result); len(problems) > 0 {
+		fmt.Println()
+		for _, problem := range problems {
+			fmt.Printf("❌ %s\n", problem)
+		}
+		return fmt.Errorf("hook '%s' produced invalid output", hookName)
+	}
+
+	fmt.Println("\n✓ Hook output is valid")
+	return nil
+}
+
+// hookRunResult captures the outcome of running a hook command
+type hookRunResult struct {
+	ExitCode int
+	Stdout   string
+	Stderr   string
+}
+
+// runHookCommand runs a hook command through bash with the payload on stdin
+func runHookCommand(command string, payload []byte, timeout time.Duration) (*hookRunResult, error) {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "bash", "-c", command)
+	cmd.Dir = wd
+	cmd.Env = append(os.Environ(), "CLAUDE_PROJECT_DIR="+wd)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("hook timed out after %s", timeout)
+	}
+
+	result := &hookRunResult{Stdout: stdout.String(), Stderr: stderr.String()}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		return nil, fmt.Errorf("failed to run hook command: %w", err)
+	}
+
+	return result, nil
+}
+
+func describeHookExitCode(code int) string {
+	switch code {
+	case 0:
+		return "success"
+	case 2:
+		return "blocking error, stderr is fed back to Claude"
+	default:
+		return "non-blocking error"
+	}
+}
+
+// validateHookOutput checks a hook's stdout against the output schema for its event
+func validateHookOutput(event types.HookEvent, result *hookRunResult) []string {
+	var problems []string
+
+	if result.ExitCode != 0 && result.ExitCode != 2 {
+		problems = append(problems, fmt.Sprintf("hook exited with code %d", result.ExitCode))
+	}
+
+	stdout := strings.TrimSpace(result.Stdout)
+	if stdout == "" || result.ExitCode != 0 {
+		return problems
+	}
+
+	var output map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		// Plain text output is allowed and shown in the transcript
+		return problems
+	}
+
+	if decision, ok := output["decision"]; ok {
+		if decision != "block" && decision != "approve" {
+			problems = append(problems, fmt.Sprintf("unknown decision %v", decision))
+		}
+		if decision == "block" {
+			if reason, _ := output["reason"].(string); reason == "" {
+				problems = append(problems, "decision \"block\" requires a reason")
+			}
+		}
+	}
+
+	if specific, ok := output["hookSpecificOutput"].(map[string]interface{}); ok {
+		if name, _ := specific["hookEventName"].(string); name != string(event) {
+			problems = append(problems, fmt.Sprintf("hookSpecificOutput.hookEventName is %q, expected %q", name, event))
+		}
+		if permission, ok := specific["permissionDecision"]; ok {
+			if event != types.PreToolUse {
+				problems = append(problems, "permissionDecision is only valid for PreToolUse hooks")
+			} else if permission != "allow" && permission != "deny" && permission != "ask" {
+				problems = append(problems, fmt.Sprintf("unknown permissionDecision %v", permission))
+			}
+		}
+	}
+
+	return problems
+}
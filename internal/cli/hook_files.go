@@ -0,0 +1,588 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+// validateHookDeclarations checks a hook's files, config, questions and cleanup entries
+func validateHookDeclarations(hook *types.Hook) error {
+	for _, file := range hook.Files {
+		if (file.Source == "") == (file.Sound == "") {
+			return fmt.Errorf("file '%s' must set exactly one of source or sound", file.Dest)
+		}
+		if err := validateHookPath(file.Dest); err != nil {
+			return fmt.Errorf("file '%s': %w", file.Dest, err)
+		}
+		if file.Source != "" && (filepath.IsAbs(file.Source) || strings.HasPrefix(filepath.Clean(file.Source), "..")) {
+			return fmt.Errorf("file source '%s' must be inside the hook's template directory", file.Source)
+		}
+		if _, err := parseFileMode(file.Mode); err != nil {
+			return fmt.Errorf("file '%s': %w", file.Dest, err)
+		}
+	}
+
+	configs := make(map[string]bool)
+	for _, hookConfig := range hook.Config {
+		if err := validateHookPath(hookConfig.Path); err != nil {
+			return fmt.Errorf("config '%s': %w", hookConfig.Path, err)
+		}
+		configs[hookConfig.Path] = true
+	}
+
+	for _, question := range hook.Questions {
+		if len(hook.Config) == 0 {
+			return fmt.Errorf("question '%s' has no config file to store its answer", question.Prompt)
+		}
+		if question.Config != "" && !configs[question.Config] {
+			return fmt.Errorf("question '%s' refers to undeclared config '%s'", question.Prompt, question.Config)
+		}
+		switch question.Type {
+		case "", types.QuestionString, types.QuestionBool, types.QuestionMappings:
+			if question.Key == "" {
+				return fmt.Errorf("question '%s' needs a key", question.Prompt)
+			}
+		case types.QuestionChoice:
+			if len(question.Choices) == 0 {
+				return fmt.Errorf("choice question '%s' has no choices", question.Prompt)
+			}
+		default:
+			return fmt.Errorf("question '%s' has unknown type '%s'", question.Prompt, question.Type)
+		}
+		if question.Pattern != "" {
+			if _, err := regexp.Compile(question.Pattern); err != nil {
+				return fmt.Errorf("question '%s' has an invalid pattern: %w", question.Prompt, err)
+			}
+		}
+	}
+
+	for _, cleanup := range hook.Cleanup {
+		if err := validateHookPath(cleanup.Path); err != nil {
+			return fmt.Errorf("cleanup '%s': %w", cleanup.Path, err)
+		}
+	}
+	return nil
+}
+
+// validateHookPath only allows hooks to touch paths inside the project's .claude directory
+func validateHookPath(path string) error {
+	if path == "" {
+		return fmt.Errorf("path is required")
+	}
+	clean := filepath.ToSlash(filepath.Clean(path))
+	if filepath.IsAbs(path) || !strings.HasPrefix(clean, ".claude/") {
+		return fmt.Errorf("path must be inside .claude/")
+	}
+	return nil
+}
+
+// parseFileMode parses an octal file mode such as "0755", defaulting to 0644
+func parseFileMode(mode string) (os.FileMode, error) {
+	if mode == "" {
+		return 0644, nil
+	}
+	value, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid mode '%s'", mode)
+	}
+	return os.FileMode(value), nil
+}
+
+// copyHookFiles copies the files a hook declares into the project
+func copyHookFiles(hook *types.Hook, template *assets.Template) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	for _, file := range hook.Files {
+		targetPath := filepath.Join(wd, filepath.FromSlash(file.Dest))
+		if file.KeepExisting {
+			if _, err := os.Stat(targetPath); err == nil {
+				fmt.Printf("File already exists: %s\n", targetPath)
+				continue
+			}
+		}
+
+		content, err := readHookFile(template, file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file.Dest, err)
+		}
+
+		mode, _ := parseFileMode(file.Mode)
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", file.Dest, err)
+		}
+		if err := os.WriteFile(targetPath, content, mode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Dest, err)
+		}
+		// WriteFile keeps the mode of an existing file
+		if err := os.Chmod(targetPath, mode); err != nil {
+			return fmt.Errorf("failed to set mode of %s: %w", file.Dest, err)
+		}
+		fmt.Printf("Copied file to: %s\n", targetPath)
+	}
+	return nil
+}
+
+// readHookFile returns the content a hook file is copied from
+func readHookFile(template *assets.Template, file types.HookFile) ([]byte, error) {
+	if file.Sound != "" {
+		return assets.ReadSound(file.Sound)
+	}
+	return template.ReadSibling(filepath.ToSlash(file.Source))
+}
+
+// hookFileDest returns where a hook file is copied, as the lockfile records it
+func hookFileDest(file types.HookFile) string {
+	return path.Clean(filepath.ToSlash(file.Dest))
+}
+
+// trackedHookFiles returns the files a hook copies that the lockfile tracks.
+// Files with keep_existing belong to the user once copied, so they aren't tracked.
+func trackedHookFiles(template *assets.Template) ([]types.HookFile, error) {
+	content, err := template.ReadFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hook template: %w", err)
+	}
+	hook, err := parseHookTemplate(content, types.HookFormatForPath(template.Path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse hook template: %w", err)
+	}
+
+	var files []types.HookFile
+	for _, file := range hook.Files {
+		if !file.KeepExisting {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// writeHookConfigs writes each config file a hook declares that doesn't exist yet,
+// starting from its defaults and applying the answers to the hook's questions.
+// Questions answered in params (see questionKey) or remembered aren't asked; the
+// answers used are returned so they can be recorded in the lockfile. Remembered
+// answers to questions the hook no longer asks, or no longer accepts, are ignored.
+func writeHookConfigs(hook *types.Hook, params, remembered map[string]string) (map[string]string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	if err := checkHookParams(hook, params); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	interactive := true
+	answers := make(map[string]string)
+
+	for _, hookConfig := range hook.Config {
+		configPath := filepath.Join(wd, filepath.FromSlash(hookConfig.Path))
+		if _, err := os.Stat(configPath); err == nil {
+			fmt.Printf("🔧 Config %s already exists, skipping configuration\n", hookConfig.Path)
+			continue
+		}
+
+		values := copyConfigValue(hookConfig.Defaults).(map[string]interface{})
+
+		for _, question := range hook.Questions {
+			target := question.Config
+			if target == "" {
+				target = hook.Config[0].Path
+			}
+			if target != hookConfig.Path {
+				continue
+			}
+
+			key := questionKey(question)
+			if answer, ok := params[key]; ok {
+				if err := applyQuestionAnswer(question, answer, values); err != nil {
+					return nil, fmt.Errorf("question '%s': %w", key, err)
+				}
+				answers[key] = answer
+				continue
+			}
+			if answer, ok := remembered[key]; ok {
+				err := applyQuestionAnswer(question, answer, values)
+				if err == nil {
+					answers[key] = answer
+					continue
+				}
+				fmt.Printf("⚠️  Saved answer for '%s' no longer applies: %v\n", key, err)
+			}
+			if interactive {
+				var answer string
+				answer, interactive = askHookQuestion(reader, question, values)
+				if answer != "" {
+					answers[key] = answer
+				}
+			} else {
+				applyQuestionDefault(question, values)
+			}
+		}
+
+		if err := saveHookConfig(configPath, values); err != nil {
+			return nil, err
+		}
+		fmt.Printf("📝 Config saved to: %s\n", configPath)
+	}
+	return answers, nil
+}
+
+// checkHookParams rejects --set values that don't answer one of the hook's questions
+func checkHookParams(hook *types.Hook, params map[string]string) error {
+	known := make(map[string]bool)
+	for _, question := range hook.Questions {
+		known[questionKey(question)] = true
+	}
+	for key := range params {
+		if !known[key] {
+			return fmt.Errorf("hook has no question '%s'", key)
+		}
+	}
+	return nil
+}
+
+// questionKey names a question in --set and the lockfile: its config key, or
+// its prompt for choice questions that set several keys
+func questionKey(question types.HookQuestion) string {
+	if question.Key != "" {
+		return question.Key
+	}
+	return question.Prompt
+}
+
+// applyQuestionAnswer stores a recorded or --set answer in values, in the form
+// askHookQuestion returns it
+func applyQuestionAnswer(question types.HookQuestion, answer string, values map[string]interface{}) error {
+	switch question.Type {
+	case types.QuestionChoice:
+		number, err := strconv.Atoi(answer)
+		if err != nil || number < 1 || number > len(question.Choices) {
+			return fmt.Errorf("invalid choice '%s', use 1-%d", answer, len(question.Choices))
+		}
+		applyChoice(question.Choices[number-1], values)
+	case types.QuestionBool:
+		value, err := strconv.ParseBool(answer)
+		if err != nil {
+			return fmt.Errorf("invalid answer '%s', use true or false", answer)
+		}
+		setConfigValue(values, question.Key, value)
+	case types.QuestionMappings:
+		var mappings map[string]interface{}
+		if err := json.Unmarshal([]byte(answer), &mappings); err != nil {
+			return fmt.Errorf("mappings must be a JSON object: %w", err)
+		}
+		setConfigValue(values, question.Key, mappings)
+	default:
+		setConfigValue(values, question.Key, answer)
+	}
+	return nil
+}
+
+// askHookQuestion asks one question and stores the answer in values. It returns
+// the answer as applyQuestionAnswer takes it, empty when the default was used,
+// and false once no interactive input is available, after applying the default.
+func askHookQuestion(reader *bufio.Reader, question types.HookQuestion, values map[string]interface{}) (string, bool) {
+	if question.Help != "" {
+		fmt.Println(strings.TrimRight(question.Help, "\n"))
+	}
+
+	switch question.Type {
+	case types.QuestionChoice:
+		for i, choice := range question.Choices {
+			fmt.Printf("%d. %s\n", i+1, choice.Label)
+		}
+		for {
+			fmt.Printf("%s (1-%d) [%s]: ", question.Prompt, len(question.Choices), choiceDefault(question))
+			input, err := reader.ReadString('\n')
+			if err != nil {
+				fmt.Println()
+				applyQuestionDefault(question, values)
+				return "", false
+			}
+			input = strings.TrimSpace(input)
+			if input == "" {
+				input = choiceDefault(question)
+			}
+			number, err := strconv.Atoi(input)
+			if err != nil || number < 1 || number > len(question.Choices) {
+				fmt.Printf("❌ Invalid choice, enter 1-%d\n", len(question.Choices))
+				continue
+			}
+			applyChoice(question.Choices[number-1], values)
+			fmt.Printf("✅ %s\n", question.Choices[number-1].Label)
+			return strconv.Itoa(number), true
+		}
+
+	case types.QuestionMappings:
+		interactive := askMappings(reader, question, values)
+		answer := ""
+		if mappings, _ := getConfigValue(values, question.Key).(map[string]interface{}); len(mappings) > 0 {
+			if data, err := json.Marshal(mappings); err == nil {
+				answer = string(data)
+			}
+		}
+		return answer, interactive
+
+	case types.QuestionBool:
+		defaultValue := question.Default == "true"
+		hint := "y/N"
+		if defaultValue {
+			hint = "Y/n"
+		}
+		fmt.Printf("%s (%s): ", question.Prompt, hint)
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Println()
+			setConfigValue(values, question.Key, defaultValue)
+			return "", false
+		}
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "y", "yes":
+			setConfigValue(values, question.Key, true)
+			return "true", true
+		case "n", "no":
+			setConfigValue(values, question.Key, false)
+			return "false", true
+		default:
+			setConfigValue(values, question.Key, defaultValue)
+			return "", true
+		}
+
+	default:
+		fmt.Printf("%s [%s]: ", question.Prompt, question.Default)
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Println()
+			applyQuestionDefault(question, values)
+			return "", false
+		}
+		if input = strings.TrimSpace(input); input != "" {
+			setConfigValue(values, question.Key, input)
+			return input, true
+		}
+		applyQuestionDefault(question, values)
+		return "", true
+	}
+}
+
+// askMappings reads key/value pairs until an empty key is entered and merges
+// them into the map stored under the question's key
+func askMappings(reader *bufio.Reader, question types.HookQuestion, values map[string]interface{}) bool {
+	mappings, _ := getConfigValue(values, question.Key).(map[string]interface{})
+	if mappings == nil {
+		mappings = make(map[string]interface{})
+	}
+	defer setConfigValue(values, question.Key, mappings)
+
+	var pattern *regexp.Regexp
+	if question.Pattern != "" {
+		pattern = regexp.MustCompile(question.Pattern)
+	}
+	valuePrompt := question.ValuePrompt
+	if valuePrompt == "" {
+		valuePrompt = "Enter value"
+	}
+
+	added := 0
+	for {
+		fmt.Printf("%s: ", question.Prompt)
+		key, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Println("\nNo interactive input available, using default configuration")
+			return false
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			break
+		}
+		if pattern != nil && !pattern.MatchString(key) {
+			fmt.Printf("❌ Invalid entry '%s' (must match %s)\n", key, question.Pattern)
+			continue
+		}
+
+		fmt.Printf("%s for '%s': ", valuePrompt, key)
+		value, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Println()
+			return false
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			fmt.Println("❌ Value cannot be empty")
+			continue
+		}
+
+		if existing, exists := mappings[key]; exists {
+			fmt.Printf("⚠️  '%s' already exists with value: '%v'\n", key, existing)
+			fmt.Print("Overwrite? (y/N): ")
+			confirm, _ := reader.ReadString('\n')
+			if strings.ToLower(strings.TrimSpace(confirm)) != "y" {
+				continue
+			}
+		}
+
+		mappings[key] = value
+		fmt.Printf("✅ Added mapping: '%s' → '%s'\n", key, value)
+		added++
+	}
+
+	if added > 0 {
+		fmt.Printf("📝 Total new mappings added: %d\n", added)
+	}
+	return true
+}
+
+// applyQuestionDefault stores a question's default answer in values
+func applyQuestionDefault(question types.HookQuestion, values map[string]interface{}) {
+	switch question.Type {
+	case types.QuestionChoice:
+		if number, err := strconv.Atoi(choiceDefault(question)); err == nil && number >= 1 && number <= len(question.Choices) {
+			applyChoice(question.Choices[number-1], values)
+		}
+	case types.QuestionBool:
+		setConfigValue(values, question.Key, question.Default == "true")
+	case types.QuestionMappings:
+		// Defaults come from the config's defaults
+	default:
+		if question.Default != "" {
+			setConfigValue(values, question.Key, question.Default)
+		}
+	}
+}
+
+func choiceDefault(question types.HookQuestion) string {
+	if question.Default == "" {
+		return "1"
+	}
+	return question.Default
+}
+
+func applyChoice(choice types.HookChoice, values map[string]interface{}) {
+	for key, value := range choice.Set {
+		setConfigValue(values, key, copyConfigValue(value))
+	}
+}
+
+// setConfigValue sets a dot-separated key, creating intermediate objects as needed
+func setConfigValue(values map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(key, ".")
+	current := values
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
+
+// getConfigValue returns the value at a dot-separated key, or nil
+func getConfigValue(values map[string]interface{}, key string) interface{} {
+	var current interface{} = values
+	for _, part := range strings.Split(key, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[part]
+	}
+	return current
+}
+
+// copyConfigValue deep-copies YAML-decoded values so defaults aren't modified
+func copyConfigValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = copyConfigValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyConfigValue(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// saveHookConfig writes a hook config file as indented JSON without HTML escaping
+func saveHookConfig(configPath string, values map[string]interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(values); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.WriteFile(configPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// loadInstalledHookDefinition returns the template definition of an installed hook,
+// preferring the template recorded in the lockfile. It returns nil if none is found.
+func loadInstalledHookDefinition(name string) *types.Hook {
+	ref := name
+	if lock, err := config.LoadLockfile(); err == nil {
+		if entry, ok := lock.Components[types.LockKey("hook", name, "")]; ok && entry.Template != "" {
+			ref = entry.Template
+		}
+	}
+
+	template, err := assets.FindTemplate("hook", ref)
+	if err != nil {
+		return nil
+	}
+	content, err := template.ReadFile()
+	if err != nil {
+		return nil
+	}
+	hook, err := parseHookTemplate(content, types.HookFormatForPath(template.Path))
+	if err != nil {
+		return nil
+	}
+	return hook
+}
+
+// hookRemovalPaths returns the paths a hook declares that are removed with it:
+// its config files, its copied files that aren't shared, and its cleanup entries
+func hookRemovalPaths(hook *types.Hook) []types.HookCleanup {
+	var paths []types.HookCleanup
+	for _, file := range hook.Files {
+		if !file.KeepExisting {
+			paths = append(paths, types.HookCleanup{Path: file.Dest})
+		}
+	}
+	for _, hookConfig := range hook.Config {
+		paths = append(paths, types.HookCleanup{Path: hookConfig.Path})
+	}
+	return append(paths, hook.Cleanup...)
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+func TestWriteHookConfigs(t *testing.T) {
+	hook := &types.Hook{
+		Config: []types.HookConfig{{
+			Path:     ".claude/config/notify.json",
+			Defaults: map[string]interface{}{"mode": "quiet", "sound": true},
+		}},
+		Questions: []types.HookQuestion{
+			{Key: "sound", Type: types.QuestionBool, Prompt: "Play a sound?", Default: "true"},
+			{Type: types.QuestionChoice, Prompt: "Mode", Default: "1", Choices: []types.HookChoice{
+				{Label: "Quiet", Set: map[string]interface{}{"mode": "quiet"}},
+				{Label: "Loud", Set: map[string]interface{}{"mode": "loud"}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		params      map[string]string
+		remembered  map[string]string
+		wantConfig  map[string]interface{}
+		wantAnswers map[string]string
+		wantErr     string
+	}{
+		{
+			name:        "defaults",
+			wantConfig:  map[string]interface{}{"mode": "quiet", "sound": true},
+			wantAnswers: map[string]string{},
+		},
+		{
+			name:        "params",
+			params:      map[string]string{"sound": "false", "Mode": "2"},
+			wantConfig:  map[string]interface{}{"mode": "loud", "sound": false},
+			wantAnswers: map[string]string{"sound": "false", "Mode": "2"},
+		},
+		{
+			name:        "remembered answers",
+			remembered:  map[string]string{"sound": "false", "Mode": "2"},
+			wantConfig:  map[string]interface{}{"mode": "loud", "sound": false},
+			wantAnswers: map[string]string{"sound": "false", "Mode": "2"},
+		},
+		{
+			name:        "params win over remembered answers",
+			params:      map[string]string{"Mode": "1"},
+			remembered:  map[string]string{"Mode": "2"},
+			wantConfig:  map[string]interface{}{"mode": "quiet", "sound": true},
+			wantAnswers: map[string]string{"Mode": "1"},
+		},
+		{
+			name:        "remembered answers to removed questions are ignored",
+			remembered:  map[string]string{"sound": "false", "volume": "11"},
+			wantConfig:  map[string]interface{}{"mode": "quiet", "sound": false},
+			wantAnswers: map[string]string{"sound": "false"},
+		},
+		{
+			name:        "remembered answers that no longer apply are ignored",
+			remembered:  map[string]string{"Mode": "3"},
+			wantConfig:  map[string]interface{}{"mode": "quiet", "sound": true},
+			wantAnswers: map[string]string{},
+		},
+		{
+			name:    "unknown param",
+			params:  map[string]string{"volume": "11"},
+			wantErr: "hook has no question 'volume'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := useTempProject(t)
+			useEmptyStdin(t)
+
+			answers, err := writeHookConfigs(hook, tt.params, tt.remembered)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("writeHookConfigs() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("writeHookConfigs() error = %v", err)
+			}
+			if !reflect.DeepEqual(answers, tt.wantAnswers) {
+				t.Errorf("writeHookConfigs() answers = %v, want %v", answers, tt.wantAnswers)
+			}
+
+			data, err := os.ReadFile(filepath.Join(project, ".claude", "config", "notify.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var config map[string]interface{}
+			if err := json.Unmarshal(data, &config); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(config, tt.wantConfig) {
+				t.Errorf("config = %v, want %v", config, tt.wantConfig)
+			}
+		})
+	}
+}
+
+// useEmptyStdin makes prompts read end of input, so questions take their defaults
+func useEmptyStdin(t *testing.T) {
+	t.Helper()
+
+	stdin, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := os.Stdin
+	os.Stdin = stdin
+	t.Cleanup(func() {
+		os.Stdin = original
+		stdin.Close()
+	})
+}
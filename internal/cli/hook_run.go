@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/expander"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+var hookRunCmd = &cobra.Command{
+	Use:   "run <hook-name>",
+	Short: "Run a hook implemented by cchp",
+	Long: `Run one of the hooks built into cchp with the hook payload on stdin.
+
+This is what the commands of these hooks call; Claude Code runs it with the
+JSON payload for the event. Errors are logged to .claude/hook-error.log and
+never block the prompt.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBuiltinHook,
+}
+
+// builtinHooks are the hooks cchp runs itself, keyed by hook name
+var builtinHooks = map[string]func(payload []byte, stdout io.Writer) error{
+	"text-expander": runTextExpanderHook,
+}
+
+func init() {
+	hookCmd.AddCommand(hookRunCmd)
+}
+
+func runBuiltinHook(cmd *cobra.Command, args []string) error {
+	run, ok := builtinHooks[args[0]]
+	if !ok {
+		return fmt.Errorf("no built-in hook named '%s'", args[0])
+	}
+
+	payload, err := io.ReadAll(os.Stdin)
+	if err == nil {
+		err = run(payload, os.Stdout)
+	}
+	if err != nil {
+		// A failing hook must not get in the way of the user's prompt
+		logHookError(args[0], err)
+	}
+	return nil
+}
+
+// logHookError appends a hook failure to .claude/hook-error.log
+func logHookError(name string, hookErr error) {
+	logPath := filepath.Join(".claude", "hook-error.log")
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "%s %s error: %v\n", time.Now().Format(time.RFC3339), name, hookErr)
+}
+
+// runTextExpanderHook expands markers in a UserPromptSubmit prompt and passes
+// the expanded prompt to Claude as additional context
+func runTextExpanderHook(payload []byte, stdout io.Writer) error {
+	var input struct {
+		Prompt string `json:"prompt"`
+	}
+	if err := json.Unmarshal(payload, &input); err != nil {
+		return fmt.Errorf("failed to parse hook payload: %w", err)
+	}
+	prompt := strings.ToValidUTF8(input.Prompt, "")
+	if prompt == "" {
+		return nil
+	}
+
+	textConfig, _, err := loadMergedTextExpanderConfig()
+	if err != nil {
+		return err
+	}
+
+	e, err := newTextExpander(textConfig)
+	if err != nil {
+		return err
+	}
+	expanded, matches := e.Expand(prompt)
+	if len(matches) == 0 && expanded == prompt {
+		return nil
+	}
+	// Values that failed expand to nothing; the rest of the prompt still goes through
+	for _, match := range matches {
+		if match.Err != nil {
+			logHookError("text-expander", fmt.Errorf("%s: %w", match.Marker, match.Err))
+		}
+	}
+
+	output, _, err := textExpanderHookOutput(textConfig, expanded, matches)
+	if err != nil {
+		// The output still falls back to the default mode
+		logHookError("text-expander", err)
+	}
+	return json.NewEncoder(stdout).Encode(output)
+}
+
+// textExpanderHookOutput returns the hook output for an expanded prompt and the
+// mode used. A marker's own mode overrides the config's, and block wins over
+// context when the fired markers disagree. Invalid modes are reported and
+// treated as context.
+func textExpanderHookOutput(textConfig *types.TextExpanderConfig, expanded string, matches []expander.Match) (map[string]interface{}, string, error) {
+	var invalid []string
+	checkMode := func(mode string) string {
+		if mode != types.TextExpanderModeContext && mode != types.TextExpanderModeBlock {
+			invalid = append(invalid, mode)
+			return types.TextExpanderModeContext
+		}
+		return mode
+	}
+
+	mode := types.TextExpanderModeContext
+	if textConfig.Mode != "" {
+		mode = checkMode(textConfig.Mode)
+	}
+	for _, match := range matches {
+		if markerMode := textConfig.Modes[match.Marker]; markerMode != "" && checkMode(markerMode) == types.TextExpanderModeBlock {
+			mode = types.TextExpanderModeBlock
+		}
+	}
+
+	var err error
+	if len(invalid) > 0 {
+		err = fmt.Errorf("invalid mode '%s' (use %s or %s)", strings.Join(invalid, "', '"), types.TextExpanderModeContext, types.TextExpanderModeBlock)
+	}
+
+	if mode == types.TextExpanderModeBlock {
+		template := textConfig.BlockTemplate
+		if template == "" {
+			template = types.DefaultTextExpanderBlockTemplate
+		}
+		return map[string]interface{}{
+			"decision": "block",
+			"reason":   strings.ReplaceAll(template, "{{prompt}}", expanded),
+		}, mode, err
+	}
+
+	template := textConfig.ContextTemplate
+	if template == "" {
+		template = types.DefaultTextExpanderContextTemplate
+	}
+	return map[string]interface{}{
+		"hookSpecificOutput": map[string]interface{}{
+			"hookEventName":     "UserPromptSubmit",
+			"additionalContext": strings.ReplaceAll(template, "{{prompt}}", expanded),
+		},
+	}, mode, err
+}
@@ -0,0 +1,505 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+// hookPayloadField describes one field of the JSON payload Claude Code sends to a hook
+type hookPayloadField struct {
+	Name   string
+	Kind   string      // "string", "bool" or "object"
+	Sample interface{} // Value used when building a sample payload for 'hook test'
+}
+
+// hookEventSpec describes the payload and output conventions of a hook event
+type hookEventSpec struct {
+	Fields         []hookPayloadField // Event-specific payload fields
+	Action         string             // "permission", "block", "context" or "none"
+	DefaultMatcher string
+}
+
+// commonHookFields are sent to every hook regardless of event
+var commonHookFields = []hookPayloadField{
+	{Name: "session_id", Kind: "string", Sample: "cchp-test-session"},
+	{Name: "transcript_path", Kind: "string", Sample: ""},
+	{Name: "cwd", Kind: "string", Sample: "."},
+	{Name: "hook_event_name", Kind: "string"},
+}
+
+var hookEventSpecs = map[types.HookEvent]hookEventSpec{
+	types.PreToolUse: {
+		Fields: []hookPayloadField{
+			{Name: "tool_name", Kind: "string", Sample: "Write"},
+			{Name: "tool_input", Kind: "object", Sample: map[string]interface{}{"file_path": "example.txt", "content": "hello"}},
+		},
+		Action:         "permission",
+		DefaultMatcher: "Edit|Write",
+	},
+	types.PostToolUse: {
+		Fields: []hookPayloadField{
+			{Name: "tool_name", Kind: "string", Sample: "Write"},
+			{Name: "tool_input", Kind: "object", Sample: map[string]interface{}{"file_path": "example.txt", "content": "hello"}},
+			{Name: "tool_response", Kind: "object", Sample: map[string]interface{}{"filePath": "example.txt", "success": true}},
+		},
+		Action:         "block",
+		DefaultMatcher: "Edit|Write",
+	},
+	types.UserPromptSubmit: {
+		Fields: []hookPayloadField{
+			{Name: "prompt", Kind: "string", Sample: "Explain this code"},
+		},
+		Action: "block",
+	},
+	types.Notification: {
+		Fields: []hookPayloadField{
+			{Name: "message", Kind: "string", Sample: "Claude needs your permission to use Bash"},
+		},
+		Action: "none",
+	},
+	types.Stop: {
+		Fields: []hookPayloadField{
+			{Name: "stop_hook_active", Kind: "bool", Sample: false},
+		},
+		Action: "block",
+	},
+	types.SubagentStop: {
+		Fields: []hookPayloadField{
+			{Name: "stop_hook_active", Kind: "bool", Sample: false},
+		},
+		Action: "block",
+	},
+	types.PreCompact: {
+		Fields: []hookPayloadField{
+			{Name: "trigger", Kind: "string", Sample: "manual"},
+			{Name: "custom_instructions", Kind: "string", Sample: ""},
+		},
+		Action:         "none",
+		DefaultMatcher: "manual|auto",
+	},
+	types.SessionStart: {
+		Fields: []hookPayloadField{
+			{Name: "source", Kind: "string", Sample: "startup"},
+		},
+		Action:         "context",
+		DefaultMatcher: "startup",
+	},
+	types.SessionEnd: {
+		Fields: []hookPayloadField{
+			{Name: "reason", Kind: "string", Sample: "exit"},
+		},
+		Action: "none",
+	},
+}
+
+// hookScaffoldLanguages maps --lang values to script extensions and hook commands
+var hookScaffoldLanguages = map[string]struct {
+	Ext     string
+	Command string
+}{
+	"python": {Ext: ".py", Command: "bash .claude/hooks/run-python.sh .claude/hooks/%s.py"},
+	"bash":   {Ext: ".sh", Command: "bash .claude/hooks/%s.sh"},
+	"node":   {Ext: ".js", Command: "node .claude/hooks/%s.js"},
+	"go":     {Ext: ".go", Command: "go run .claude/hooks/%s.go"},
+}
+
+type hookScaffoldData struct {
+	Name        string
+	Event       string
+	TypeName    string
+	Action      string
+	Fields      []hookPayloadField // Common and event-specific fields
+	EventFields []hookPayloadField
+}
+
+// generateHookScript renders a script skeleton that parses the typed payload of
+// the given event and prints a valid hook decision
+func generateHookScript(name string, event types.HookEvent, lang string) (string, error) {
+	spec, ok := hookEventSpecs[event]
+	if !ok {
+		return "", fmt.Errorf("unsupported hook event: %s", event)
+	}
+
+	source, ok := hookScriptTemplates[lang]
+	if !ok {
+		return "", fmt.Errorf("unsupported hook language: %s", lang)
+	}
+
+	data := hookScaffoldData{
+		Name:        name,
+		Event:       string(event),
+		TypeName:    string(event) + "Payload",
+		Action:      spec.Action,
+		Fields:      append(append([]hookPayloadField{}, commonHookFields...), spec.Fields...),
+		EventFields: spec.Fields,
+	}
+
+	tmpl, err := template.New(lang).Funcs(hookScaffoldFuncs).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s script template: %w", lang, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s script: %w", lang, err)
+	}
+
+	if lang == "go" {
+		formatted, err := format.Source(buf.Bytes())
+		if err != nil {
+			return "", fmt.Errorf("failed to format Go script: %w", err)
+		}
+		return string(formatted), nil
+	}
+	return buf.String(), nil
+}
+
+// buildSampleHookPayload returns a representative payload for an event
+func buildSampleHookPayload(event types.HookEvent) map[string]interface{} {
+	payload := make(map[string]interface{})
+	for _, field := range commonHookFields {
+		payload[field.Name] = field.Sample
+	}
+	payload["hook_event_name"] = string(event)
+
+	for _, field := range hookEventSpecs[event].Fields {
+		payload[field.Name] = field.Sample
+	}
+	return payload
+}
+
+var hookScaffoldFuncs = template.FuncMap{
+	"goName": func(name string) string {
+		var b strings.Builder
+		for _, part := range strings.Split(name, "_") {
+			switch part {
+			case "id", "cwd":
+				b.WriteString(strings.ToUpper(part))
+			default:
+				b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+			}
+		}
+		return b.String()
+	},
+	"goType": func(kind string) string {
+		switch kind {
+		case "bool":
+			return "bool"
+		case "object":
+			return "map[string]any"
+		default:
+			return "string"
+		}
+	},
+	"pyType": func(kind string) string {
+		switch kind {
+		case "bool":
+			return "bool"
+		case "object":
+			return "Dict[str, Any]"
+		default:
+			return "str"
+		}
+	},
+	"pyDefault": func(kind string) string {
+		switch kind {
+		case "bool":
+			return "False"
+		case "object":
+			return "{}"
+		default:
+			return `""`
+		}
+	},
+	"jsType": func(kind string) string {
+		switch kind {
+		case "bool":
+			return "{boolean}"
+		case "object":
+			return "{Object<string, *>}"
+		default:
+			return "{string}"
+		}
+	},
+	"jsDefault": func(kind string) string {
+		switch kind {
+		case "bool":
+			return "false"
+		case "object":
+			return "{}"
+		default:
+			return `""`
+		}
+	},
+	"jqFilter": func(field hookPayloadField) string {
+		switch field.Kind {
+		case "bool":
+			return fmt.Sprintf("jq -r '.%s // false'", field.Name)
+		case "object":
+			return fmt.Sprintf("jq -c '.%s // {}'", field.Name)
+		default:
+			return fmt.Sprintf("jq -r '.%s // empty'", field.Name)
+		}
+	},
+	"brace": func(s string) string {
+		return "{" + s + "}"
+	},
+}
+
+var hookScriptTemplates = map[string]string{
+	"python": `#!/usr/bin/env python3
+"""{{.Name}} - {{.Event}} hook generated by cchp.
+
+Reads the {{.Event}} payload from stdin and prints a JSON decision to stdout.
+"""
+import json
+import sys
+from typing import Any, Dict, TypedDict
+
+
+class {{.TypeName}}(TypedDict, total=False):
+{{- range .Fields}}
+    {{.Name}}: {{pyType .Kind}}
+{{- end}}
+
+
+def decide(payload: {{.TypeName}}) -> Dict[str, Any]:
+    """Inspect the payload and return the hook output."""
+{{- range .EventFields}}
+    {{.Name}} = payload.get("{{.Name}}", {{pyDefault .Kind}})
+{{- end}}
+{{if eq .Action "permission"}}
+    # Set a reason to deny this tool call; leave it empty to defer to the normal permission flow
+    reason = ""
+    if reason:
+        return {
+            "hookSpecificOutput": {
+                "hookEventName": "PreToolUse",
+                "permissionDecision": "deny",
+                "permissionDecisionReason": reason,
+            }
+        }
+    return {}
+{{- else if eq .Action "block"}}
+    # Set a reason to block; leave it empty to let Claude Code continue normally
+    reason = ""
+    if reason:
+        return {"decision": "block", "reason": reason}
+    return {}
+{{- else if eq .Action "context"}}
+    # Text returned here is added to Claude's context
+    context = ""
+    if context:
+        return {"hookSpecificOutput": {"hookEventName": "{{.Event}}", "additionalContext": context}}
+    return {}
+{{- else}}
+    return {}
+{{- end}}
+
+
+def main() -> None:
+    try:
+        payload: {{.TypeName}} = json.load(sys.stdin)
+    except json.JSONDecodeError as e:
+        print(f"{{.Name}}: invalid hook payload: {e}", file=sys.stderr)
+        sys.exit(1)
+
+    print(json.dumps(decide(payload)))
+    sys.exit(0)
+
+
+if __name__ == "__main__":
+    main()
+`,
+
+	"node": `#!/usr/bin/env node
+// {{.Name}} - {{.Event}} hook generated by cchp.
+//
+// Reads the {{.Event}} payload from stdin and prints a JSON decision to stdout.
+
+/**
+ * @typedef {Object} {{.TypeName}}
+{{- range .Fields}}
+ * @property {{jsType .Kind}} {{.Name}}
+{{- end}}
+ */
+
+/**
+ * Inspect the payload and return the hook output.
+ * @param {{brace .TypeName}} payload
+ * @returns {Object}
+ */
+function decide(payload) {
+{{- range .EventFields}}
+  const {{.Name}} = payload.{{.Name}} ?? {{jsDefault .Kind}};
+{{- end}}
+{{if eq .Action "permission"}}
+  // Set a reason to deny this tool call; leave it empty to defer to the normal permission flow
+  const reason = "";
+  if (reason) {
+    return {
+      hookSpecificOutput: {
+        hookEventName: "PreToolUse",
+        permissionDecision: "deny",
+        permissionDecisionReason: reason,
+      },
+    };
+  }
+  return {};
+{{- else if eq .Action "block"}}
+  // Set a reason to block; leave it empty to let Claude Code continue normally
+  const reason = "";
+  if (reason) {
+    return { decision: "block", reason };
+  }
+  return {};
+{{- else if eq .Action "context"}}
+  // Text returned here is added to Claude's context
+  const context = "";
+  if (context) {
+    return { hookSpecificOutput: { hookEventName: "{{.Event}}", additionalContext: context } };
+  }
+  return {};
+{{- else}}
+  return {};
+{{- end}}
+}
+
+let input = "";
+process.stdin.setEncoding("utf8");
+process.stdin.on("data", (chunk) => {
+  input += chunk;
+});
+process.stdin.on("end", () => {
+  let payload;
+  try {
+    payload = JSON.parse(input);
+  } catch (err) {
+    console.error(` + "`{{.Name}}: invalid hook payload: ${err.message}`" + `);
+    process.exit(1);
+  }
+
+  process.stdout.write(JSON.stringify(decide(payload)) + "\n");
+  process.exit(0);
+});
+`,
+
+	"bash": `#!/bin/bash
+# {{.Name}} - {{.Event}} hook generated by cchp.
+#
+# Reads the {{.Event}} payload from stdin and prints a JSON decision to stdout.
+# Requires jq to parse the payload.
+
+set -euo pipefail
+
+if ! command -v jq > /dev/null 2>&1; then
+    echo "{{.Name}}: jq is required to parse the hook payload" >&2
+    exit 1
+fi
+
+payload="$(cat)"
+if ! printf '%s' "$payload" | jq -e . > /dev/null 2>&1; then
+    echo "{{.Name}}: invalid hook payload" >&2
+    exit 1
+fi
+
+# {{.TypeName}} fields
+{{- range .Fields}}
+{{.Name}}="$(printf '%s' "$payload" | {{jqFilter .}})"
+{{- end}}
+{{if eq .Action "permission"}}
+# Set a reason to deny this tool call; leave it empty to defer to the normal permission flow
+reason=""
+if [ -n "$reason" ]; then
+    jq -n --arg reason "$reason" \
+        '{hookSpecificOutput: {hookEventName: "PreToolUse", permissionDecision: "deny", permissionDecisionReason: $reason}}'
+    exit 0
+fi
+{{- else if eq .Action "block"}}
+# Set a reason to block; leave it empty to let Claude Code continue normally
+reason=""
+if [ -n "$reason" ]; then
+    jq -n --arg reason "$reason" '{decision: "block", reason: $reason}'
+    exit 0
+fi
+{{- else if eq .Action "context"}}
+# Text printed here is added to Claude's context
+context=""
+if [ -n "$context" ]; then
+    jq -n --arg context "$context" '{hookSpecificOutput: {hookEventName: "{{.Event}}", additionalContext: $context}}'
+    exit 0
+fi
+{{- end}}
+
+echo '{}'
+`,
+
+	"go": `// {{.Name}} - {{.Event}} hook generated by cchp.
+//
+// Reads the {{.Event}} payload from stdin and prints a JSON decision to stdout.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// {{.TypeName}} is the JSON payload Claude Code sends to {{.Event}} hooks
+type {{.TypeName}} struct {
+{{- range .Fields}}
+	{{goName .Name}} {{goType .Kind}} ` + "`json:\"{{.Name}}\"`" + `
+{{- end}}
+}
+
+// decide inspects the payload and returns the hook output
+func decide(payload {{.TypeName}}) map[string]any {
+{{- if eq .Action "permission"}}
+	// Set a reason to deny this tool call; leave it empty to defer to the normal permission flow
+	reason := ""
+	if reason != "" {
+		return map[string]any{
+			"hookSpecificOutput": map[string]any{
+				"hookEventName":            "PreToolUse",
+				"permissionDecision":       "deny",
+				"permissionDecisionReason": reason,
+			},
+		}
+	}
+{{- else if eq .Action "block"}}
+	// Set a reason to block; leave it empty to let Claude Code continue normally
+	reason := ""
+	if reason != "" {
+		return map[string]any{"decision": "block", "reason": reason}
+	}
+{{- else if eq .Action "context"}}
+	// Text returned here is added to Claude's context
+	context := ""
+	if context != "" {
+		return map[string]any{
+			"hookSpecificOutput": map[string]any{"hookEventName": "{{.Event}}", "additionalContext": context},
+		}
+	}
+{{- end}}
+	return map[string]any{}
+}
+
+func main() {
+	var payload {{.TypeName}}
+	if err := json.NewDecoder(os.Stdin).Decode(&payload); err != nil {
+		fmt.Fprintf(os.Stderr, "{{.Name}}: invalid hook payload: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(decide(payload)); err != nil {
+		fmt.Fprintf(os.Stderr, "{{.Name}}: failed to write output: %v\n", err)
+		os.Exit(1)
+	}
+}
+`,
+}
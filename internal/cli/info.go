@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/internal/manifest"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info <component-name>",
+	Short: "Show details about a component template",
+	Long: `Show details about an agent, hook, slash command or MCP server template.
+
+For agents, the fully resolved output is printed: the extends: chain and
+{{include}} fragments are flattened and template variables are rendered with
+saved values or their defaults, exactly as 'install' would write it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: showComponentInfo,
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+	infoCmd.Flags().StringArray("set", []string{}, "Preview with a template variable (key=value, repeatable)")
+}
+
+func showComponentInfo(cmd *cobra.Command, args []string) error {
+	templateRef := args[0]
+	_, componentName := assets.SplitQualifiedName(templateRef)
+	setValues, _ := cmd.Flags().GetStringArray("set")
+
+	params, err := parseSetFlags(setValues)
+	if err != nil {
+		return err
+	}
+
+	template, err := findComponentTemplate(templateRef)
+	if err != nil {
+		return fmt.Errorf("failed to find component '%s': %w", templateRef, err)
+	}
+	componentType := template.Type
+
+	content, err := template.ReadFile()
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+
+	status := "Available"
+	if installed, err := isComponentInstalled(componentName, componentType, ""); err == nil && installed {
+		status = "Installed"
+	}
+
+	fmt.Printf("Name:     %s\n", componentName)
+	fmt.Printf("Type:     %s\n", componentType)
+	fmt.Printf("Status:   %s\n", status)
+	fmt.Printf("Template: %s\n", template.Location())
+	fmt.Printf("Source:   %s\n", template.Origin)
+	if componentManifest, err := manifest.Load(template); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	} else if componentManifest != nil {
+		showManifestInfo(componentManifest)
+	}
+
+	switch componentType {
+	case "agent":
+		return showAgentInfo(componentName, content, params)
+	case "hook":
+		return showHookInfo(content, types.HookFormatForPath(template.Path))
+	case "command":
+		return showCommandInfo(componentName, content)
+	case "mcp":
+		return showMCPInfo(componentName, content)
+	case "permission":
+		return showPermissionInfo(componentName, content)
+	default:
+		return fmt.Errorf("unsupported component type: %s", componentType)
+	}
+}
+
+func showManifestInfo(m *types.ComponentManifest) {
+	if m.Version != "" {
+		fmt.Printf("Version:  %s\n", m.Version)
+	}
+	if m.Author != "" {
+		fmt.Printf("Author:   %s\n", m.Author)
+	}
+	if m.MinCchpVersion != "" {
+		fmt.Printf("Requires: cchp >= %s\n", m.MinCchpVersion)
+	}
+	if len(m.Platforms) > 0 {
+		fmt.Printf("Platforms: %s\n", strings.Join(m.Platforms, ", "))
+	}
+	if len(m.Binaries) > 0 {
+		fmt.Printf("Binaries: %s\n", strings.Join(m.Binaries, ", "))
+	}
+	if len(m.Requires.Components) > 0 {
+		fmt.Printf("Requires components: %s\n", strings.Join(m.Requires.Components, ", "))
+	}
+	if len(m.Requires.Runtime) > 0 {
+		fmt.Printf("Requires runtime: %s\n", strings.Join(m.Requires.Runtime, ", "))
+	}
+	for _, problem := range manifest.CheckCompatibility(m, Version) {
+		fmt.Printf("⚠️  %s\n", problem.Message)
+	}
+}
+
+func showAgentInfo(name string, content []byte, params map[string]string) error {
+	flattened, err := flattenAgentTemplate(name, content, nil)
+	if err != nil {
+		return err
+	}
+
+	remembered, err := config.LoadComponentParams(types.LockKey("agent", name, ""))
+	if err != nil {
+		return err
+	}
+	rendered, values, err := renderAgentTemplate(name, flattened, params, remembered, false)
+	if err != nil {
+		return err
+	}
+
+	if agent, err := types.ParseAgentFromMarkdown(rendered); err == nil {
+		fmt.Printf("Description: %s\n", agent.Description)
+	}
+
+	if len(values) > 0 {
+		fmt.Println("\nVariables:")
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("  %s = %q\n", key, values[key])
+		}
+	}
+
+	fmt.Println("\nResolved output:")
+	fmt.Println("----------------")
+	fmt.Println(rendered)
+	return nil
+}
+
+func showHookInfo(content []byte, format string) error {
+	hook, err := parseHookTemplate(content, format)
+	if err != nil {
+		return fmt.Errorf("failed to parse hook template: %w", err)
+	}
+
+	fmt.Printf("Description: %s\n", hook.Description)
+	fmt.Printf("Event:       %s\n", hook.Event)
+	if hook.Matcher != "" {
+		fmt.Printf("Matcher:     %s\n", hook.Matcher)
+	}
+	fmt.Printf("Timeout:     %ds\n", hook.Timeout)
+	fmt.Printf("Command:     %s\n", hook.Command)
+	return nil
+}
+
+func showCommandInfo(name string, content []byte) error {
+	command, err := types.ParseCommand(name, string(content))
+	if err != nil {
+		return fmt.Errorf("failed to parse command template: %w", err)
+	}
+
+	fmt.Printf("Invoke:      %s\n", types.CommandInvocation(name))
+	if command.Frontmatter.Description != "" {
+		fmt.Printf("Description: %s\n", command.Frontmatter.Description)
+	}
+	if command.Frontmatter.ArgumentHint != "" {
+		fmt.Printf("Arguments:   %s\n", command.Frontmatter.ArgumentHint)
+	}
+	if len(command.Frontmatter.AllowedTools) > 0 {
+		fmt.Printf("Tools:       %s\n", strings.Join(command.Frontmatter.AllowedTools, ", "))
+	}
+	if command.Frontmatter.Model != "" {
+		fmt.Printf("Model:       %s\n", command.Frontmatter.Model)
+	}
+
+	fmt.Println("\nPrompt:")
+	fmt.Println("-------")
+	fmt.Println(strings.TrimSpace(command.Body))
+	return nil
+}
+
+func showMCPInfo(name string, content []byte) error {
+	server, err := parseMCPTemplate(name, content)
+	if err != nil {
+		return fmt.Errorf("failed to parse MCP server template: %w", err)
+	}
+
+	if server.Description != "" {
+		fmt.Printf("Description: %s\n", server.Description)
+	}
+	fmt.Printf("Transport:   %s\n", server.Transport())
+	if server.Transport() == types.MCPTransportStdio {
+		fmt.Printf("Command:     %s\n", server.Target())
+	} else {
+		fmt.Printf("URL:         %s\n", server.URL)
+	}
+	if placeholders := server.Placeholders(false); len(placeholders) > 0 {
+		fmt.Printf("Environment: %s\n", strings.Join(placeholders, ", "))
+	}
+	return nil
+}
+
+func showPermissionInfo(name string, content []byte) error {
+	preset, err := parsePermissionPreset(name, content)
+	if err != nil {
+		return fmt.Errorf("failed to parse permission preset: %w", err)
+	}
+
+	if preset.Description != "" {
+		fmt.Printf("Description: %s\n", preset.Description)
+	}
+	if preset.DefaultMode != "" {
+		fmt.Printf("Mode:        %s\n", preset.DefaultMode)
+	}
+	for _, behavior := range types.PermissionBehaviors {
+		rules := preset.Rules(behavior)
+		if len(rules) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", strings.ToUpper(behavior[:1])+behavior[1:])
+		for _, rule := range rules {
+			fmt.Printf("  %s\n", rule)
+		}
+	}
+	return nil
+}
@@ -1,9 +1,6 @@
 package cli
 
 import (
-	"bufio"
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,16 +9,26 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 	"github.com/zxj777/claude-helper/internal/assets"
 	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/internal/manifest"
 	"github.com/zxj777/claude-helper/pkg/types"
 )
 
 var installCmd = &cobra.Command{
 	Use:   "install <component-name>",
 	Short: "Install a component to Claude Code",
-	Long:  `Install an agent or hook template to your Claude Code configuration.`,
+	Long: `Install an agent, hook, slash command, MCP server or permission preset template
+to your Claude Code configuration.
+
+Templates are resolved across project and user template directories, configured
+template sources and built-in templates, in that order. Prefix the name with a
+source to pick a template from that source explicitly, e.g. team/security-check.
+Namespaced slash commands join their subdirectories with ':', e.g.
+frontend:component installs .claude/commands/frontend/component.md.
+
+Use bundle:<name> to install every component listed in a bundle, e.g.
+cchp install bundle:essentials.`,
 	Args:  cobra.ExactArgs(1),
 	RunE:  installComponent,
 }
@@ -29,35 +36,111 @@ var installCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(installCmd)
 	installCmd.Flags().BoolP("force", "f", false, "Force install even if component already exists")
+	installCmd.Flags().StringArray("set", []string{}, "Set an agent template variable or answer a hook question (key=value, repeatable)")
+	installCmd.Flags().Bool("ignore-compatibility", false, "Install even if the component's manifest says it is incompatible")
+	installCmd.Flags().Bool("no-deps", false, "Don't install the components the manifest requires")
+	installCmd.Flags().Bool("allow-unverified", false, "Install components from git sources whose manifest has no checksums")
+}
+
+// installOptions controls how a component is installed
+type installOptions struct {
+	Force        bool
+	Params       map[string]string // --set values; each must name a variable or question the template declares
+	Defaults     map[string]string // Values remembered in the lockfile; ones the template no longer declares are ignored
+	IgnoreCompat bool
+	SkipSetup    bool   // Don't re-run a hook's setup script, e.g. when upgrading
+	Scope        string // config.ScopeUser installs agents, commands, MCP servers and permission presets for the user; empty means the project
+
+	SkipDependencies bool // Don't install the components and runtime files the manifest requires
+	AllowUnverified  bool // Install components from git sources that have no checksums
 }
 
 func installComponent(cmd *cobra.Command, args []string) error {
-	componentName := args[0]
+	// Components may be qualified with a template source, e.g. team/security-check
+	templateRef := args[0]
 	force, _ := cmd.Flags().GetBool("force")
+	setValues, _ := cmd.Flags().GetStringArray("set")
+	ignoreCompat, _ := cmd.Flags().GetBool("ignore-compatibility")
+	noDeps, _ := cmd.Flags().GetBool("no-deps")
+	allowUnverified, _ := cmd.Flags().GetBool("allow-unverified")
+
+	params, err := parseSetFlags(setValues)
+	if err != nil {
+		return err
+	}
+
+	opts := installOptions{
+		Force:            force,
+		Params:           params,
+		IgnoreCompat:     ignoreCompat,
+		SkipDependencies: noDeps,
+		AllowUnverified:  allowUnverified,
+	}
+
+	// bundle:<name> installs every component listed in a bundle
+	if bundleRef, ok := strings.CutPrefix(templateRef, bundlePrefix); ok {
+		return installBundle(bundleRef, opts)
+	}
+
+	return installComponentFromTemplate(templateRef, opts)
+}
+
+// installComponentFromTemplate installs a component and records it in the lockfile
+func installComponentFromTemplate(templateRef string, opts installOptions) error {
+	_, componentName := assets.SplitQualifiedName(templateRef)
 
 	fmt.Printf("Installing component: %s\n", componentName)
 
 	// Find the component template
-	templatePath, componentType, err := findComponentTemplate(componentName)
+	template, err := findComponentTemplate(templateRef)
 	if err != nil {
-		return fmt.Errorf("failed to find component '%s': %w", componentName, err)
+		return fmt.Errorf("failed to find component '%s': %w", templateRef, err)
 	}
+	componentType := template.Type
 
-	fmt.Printf("Found %s template at: %s\n", componentType, templatePath)
+	fmt.Printf("Found %s template at: %s\n", componentType, template.Location())
+
+	// Verify checksums and signatures before anything is copied or run
+	if err := verifyTemplateIntegrity(template, opts.AllowUnverified); err != nil {
+		return err
+	}
+
+	// Check the component's manifest against this cchp, platform and PATH
+	if err := checkComponentCompatibility(componentType, templateRef, opts.IgnoreCompat); err != nil {
+		return err
+	}
+
+	// Reject the install before any of its dependencies are installed
+	if err := validateComponentInstall(componentName, template, opts); err != nil {
+		return err
+	}
 
 	// Check if already installed (unless force is used)
-	if !force {
-		if installed, err := isComponentInstalled(componentName, componentType); err == nil && installed {
+	if !opts.Force {
+		if installed, err := isComponentInstalled(componentName, componentType, opts.Scope); err == nil && installed {
 			return fmt.Errorf("component '%s' is already installed. Use --force to reinstall", componentName)
 		}
 	}
 
+	// Install required components and shared runtime files first
+	if !opts.SkipDependencies {
+		if err := installDependencies(templateRef, opts); err != nil {
+			return err
+		}
+	}
+
 	// Install based on component type
 	switch componentType {
 	case "agent":
-		err = installAgent(componentName, templatePath)
+		err = installAgent(componentName, template, opts)
 	case "hook":
-		err = installHook(componentName, templatePath, force)
+		err = installHook(componentName, template, opts)
+	case "command":
+		err = installCommand(componentName, template, opts.Scope)
+	case "mcp":
+		err = installMCPServer(componentName, template, opts.Scope)
+	case "permission":
+		err = installPermissionPreset(componentName, template, opts.Scope)
 	default:
 		return fmt.Errorf("unsupported component type: %s", componentType)
 	}
@@ -66,42 +149,288 @@ func installComponent(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to install %s '%s': %w", componentType, componentName, err)
 	}
 
+	if err := recordInstalledComponent(componentName, template, templateRef, opts.Scope); err != nil {
+		fmt.Printf("Warning: failed to update lockfile: %v\n", err)
+	}
+
 	fmt.Printf("✓ Successfully installed %s '%s'\n", componentType, componentName)
 	return nil
 }
 
-func findComponentTemplate(name string) (templatePath string, componentType string, err error) {
+// validateComponentInstall rejects installs that would fail on their scope, their
+// --set values or a disabled agent, so they don't leave dependencies behind
+func validateComponentInstall(name string, template *assets.Template, opts installOptions) error {
+	if opts.Scope == config.ScopeLocal && template.Type != "permission" {
+		return fmt.Errorf("local scope is only supported for permission presets")
+	}
+
+	switch template.Type {
+	case "agent":
+		agentsDir, err := config.GetProjectAgentsPath()
+		if opts.Scope == config.ScopeUser {
+			agentsDir, err = config.GetUserAgentsPath()
+		}
+		if err != nil {
+			return err
+		}
+		// Never clobber an agent the user has disabled; it must be enabled or removed first
+		disabledPath := filepath.Join(agentsDir, name+".md.disabled")
+		if _, err := os.Stat(disabledPath); err == nil {
+			return fmt.Errorf("agent '%s' is installed but disabled (%s). Use 'cchp enable %s' or 'cchp remove %s' first", name, disabledPath, name, name)
+		}
+
+		// A dry render rejects --set values the agent doesn't declare
+		content, err := template.ReadFile()
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		flattened, err := flattenAgentTemplate(name, content, nil)
+		if err != nil {
+			return err
+		}
+		_, _, err = renderAgentTemplate(name, flattened, opts.Params, nil, false)
+		return err
+
+	case "hook":
+		if opts.Scope == config.ScopeUser {
+			return fmt.Errorf("user scope is only supported for agents, commands, MCP servers and permission presets")
+		}
+		content, err := template.ReadFile()
+		if err != nil {
+			return fmt.Errorf("failed to read hook template: %w", err)
+		}
+		hook, err := parseHookTemplate(content, types.HookFormatForPath(template.Path))
+		if err != nil {
+			return fmt.Errorf("failed to parse hook template: %w", err)
+		}
+		return checkHookParams(hook, opts.Params)
+
+	default:
+		if len(opts.Params) > 0 {
+			return fmt.Errorf("--set is only supported for agent and hook templates")
+		}
+	}
+	return nil
+}
+
+// recordInstalledComponent writes the component's template, version and file
+// hashes to .claude/cchp.lock, or to the user lockfile for user scope
+func recordInstalledComponent(name string, template *assets.Template, templateRef, scope string) error {
+	entry := types.LockedComponent{
+		Type:     template.Type,
+		Template: templateRef,
+		Source:   template.Origin,
+	}
+	if scope == config.ScopeUser || scope == config.ScopeLocal {
+		entry.Scope = scope
+	}
+
+	if componentManifest, err := manifest.Load(template); err == nil && componentManifest != nil {
+		entry.Version = componentManifest.Version
+	}
+	if hash, err := manifest.ContentHash(template); err == nil {
+		entry.TemplateHash = hash
+	}
+
+	files, err := readInstalledFiles(name, template, scope)
+	if err != nil {
+		return err
+	}
+
+	// Keep pristine copies so 'cchp upgrade' can merge later template changes with local edits
+	entry.Files = make(map[string]string)
+	for file, data := range files {
+		entry.Files[file] = config.HashBytes(data)
+		if err := config.SaveBaseFile(file, data); err != nil {
+			return err
+		}
+	}
+
+	return config.RecordLockedComponent(name, entry)
+}
+
+// installedComponentFiles returns the files a component installs as recorded in
+// the lockfile: relative to the project root, or to the home directory (~/) for user scope
+func installedComponentFiles(name string, template *assets.Template, scope string) ([]string, error) {
+	switch template.Type {
+	case "agent":
+		if scope == config.ScopeUser {
+			return []string{"~/.claude/agents/" + name + ".md"}, nil
+		}
+		return []string{".claude/agents/" + name + ".md"}, nil
+	case "hook":
+		files := hookScriptFiles(name)
+		declared, err := trackedHookFiles(template)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range declared {
+			if dest := hookFileDest(file); !containsString(files, dest) {
+				files = append(files, dest)
+			}
+		}
+		return files, nil
+	case "command":
+		if scope == config.ScopeUser {
+			return []string{"~/.claude/commands/" + types.CommandFilePath(name)}, nil
+		}
+		return []string{".claude/commands/" + types.CommandFilePath(name)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// hookScriptFiles returns the paths a hook's <name>.<ext> script may be installed at
+func hookScriptFiles(name string) []string {
+	var files []string
+	for _, ext := range []string{".py", ".sh", ".js", ".ts", ".go"} {
+		files = append(files, ".claude/hooks/"+name+ext)
+	}
+	return files
+}
+
+// readInstalledFiles reads the component's installed files that exist,
+// keyed by their lockfile path
+func readInstalledFiles(name string, template *assets.Template, scope string) (map[string][]byte, error) {
+	installed, err := installedComponentFiles(name, template, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	for _, file := range installed {
+		path, err := config.ResolveLockedFile(file)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		files[file] = data
+	}
+	return files, nil
+}
+
+func findComponentTemplate(name string) (*assets.Template, error) {
 	// Try to find agent template first
-	if agentPath, err := assets.GetTemplatePath("agent", name); err == nil {
-		return agentPath, "agent", nil
+	if template, err := assets.FindTemplate("agent", name); err == nil {
+		return template, nil
 	}
 
 	// Try to find hook template
-	if hookPath, err := assets.GetTemplatePath("hook", name); err == nil {
-		return hookPath, "hook", nil
+	if template, err := assets.FindTemplate("hook", name); err == nil {
+		return template, nil
+	}
+
+	// Try to find slash command template
+	if template, err := assets.FindTemplate("command", name); err == nil {
+		return template, nil
+	}
+
+	// Try to find MCP server template
+	if template, err := assets.FindTemplate("mcp", name); err == nil {
+		return template, nil
+	}
+
+	// Try to find permission preset template
+	if template, err := assets.FindTemplate("permission", name); err == nil {
+		return template, nil
+	}
+
+	return nil, fmt.Errorf("template not found: %s", name)
+}
+
+// checkComponentCompatibility warns about or refuses components whose manifest
+// declares requirements this environment does not meet
+func checkComponentCompatibility(componentType, templateRef string, ignore bool) error {
+	componentManifest, err := loadComponentManifest(componentType, templateRef)
+	if err != nil {
+		return err
+	}
+	if componentManifest == nil {
+		return nil
 	}
 
-	return "", "", fmt.Errorf("template not found: %s", name)
+	if componentManifest.Version != "" {
+		fmt.Printf("Version: %s\n", componentManifest.Version)
+	}
+
+	fatal := false
+	for _, problem := range manifest.CheckCompatibility(componentManifest, Version) {
+		if problem.Fatal && !ignore {
+			fatal = true
+			fmt.Printf("❌ %s\n", problem.Message)
+		} else {
+			fmt.Printf("⚠️  %s\n", problem.Message)
+		}
+	}
+
+	if fatal {
+		return fmt.Errorf("component '%s' is not compatible with this environment. Use --ignore-compatibility to install anyway", templateRef)
+	}
+	return nil
+}
+
+// loadComponentManifest returns the manifest of a component template, or nil if it has none
+func loadComponentManifest(componentType, templateRef string) (*types.ComponentManifest, error) {
+	template, err := assets.FindTemplate(componentType, templateRef)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Load(template)
 }
 
-func isComponentInstalled(name, componentType string) (bool, error) {
+func isComponentInstalled(name, componentType, scope string) (bool, error) {
 	switch componentType {
 	case "agent":
+		if scope == config.ScopeUser {
+			agentFile, err := config.FindAgentFile(name)
+			return agentFile != nil && agentFile.Scope == config.ScopeUser, err
+		}
 		return config.IsAgentInstalled(name)
 	case "hook":
 		return config.IsHookInstalled(name)
+	case "command":
+		commandFile, err := config.FindCommandFile(name)
+		if err != nil || commandFile == nil {
+			return false, err
+		}
+		if scope == config.ScopeUser {
+			return commandFile.Scope == config.ScopeUser, nil
+		}
+		return commandFile.Scope == config.ScopeProject && !commandFile.Disabled, nil
+	case "mcp":
+		if scope == "" {
+			scope = config.ScopeProject
+		}
+		servers, err := config.LoadMCPServers(scope)
+		if err != nil {
+			return false, err
+		}
+		_, ok := servers[name]
+		return ok, nil
+	case "permission":
+		return isPermissionPresetInstalled(name, scope)
 	default:
 		return false, fmt.Errorf("unknown component type: %s", componentType)
 	}
 }
 
-func installAgent(name, templatePath string) error {
-	// Use project-local agents directory (.claude/agents/)
-	wd, err := os.Getwd()
+func installAgent(name string, template *assets.Template, opts installOptions) error {
+	params, scope := opts.Params, opts.Scope
+
+	// Use project-local agents directory (.claude/agents/) unless installing for the user
+	agentsDir, err := config.GetProjectAgentsPath()
+	if scope == config.ScopeUser {
+		agentsDir, err = config.GetUserAgentsPath()
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
+		return err
 	}
-	agentsDir := filepath.Join(wd, ".claude", "agents")
 
 	// Create agents directory if it doesn't exist
 	if err := os.MkdirAll(agentsDir, 0755); err != nil {
@@ -109,114 +438,145 @@ func installAgent(name, templatePath string) error {
 	}
 
 	// Read template content
-	content, err := os.ReadFile(templatePath)
+	content, err := template.ReadFile()
 	if err != nil {
 		return fmt.Errorf("failed to read template: %w", err)
 	}
 
+	// Resolve extends: and {{include}} directives into a single document
+	flattened, err := flattenAgentTemplate(name, content, func(included *assets.Template) error {
+		return verifyTemplateIntegrity(included, opts.AllowUnverified)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Render template variables, reusing values remembered from earlier installs
+	lockKey := types.LockKey("agent", name, scope)
+	remembered := opts.Defaults
+	if remembered == nil {
+		if remembered, err = config.LoadComponentParams(lockKey); err != nil {
+			return err
+		}
+	}
+	rendered, values, err := renderAgentTemplate(name, flattened, params, remembered, true)
+	if err != nil {
+		return err
+	}
+
 	// Write to project-local agents directory
 	targetPath := filepath.Join(agentsDir, name+".md")
-	if err := os.WriteFile(targetPath, content, 0644); err != nil {
+	if err := os.WriteFile(targetPath, []byte(rendered), 0644); err != nil {
 		return fmt.Errorf("failed to write agent file: %w", err)
 	}
 
+	if err := config.SaveComponentParams(lockKey, values); err != nil {
+		return fmt.Errorf("failed to save template variables: %w", err)
+	}
+
 	fmt.Printf("Agent installed at: %s\n", targetPath)
 	return nil
 }
 
-func installHook(name, templatePath string, force bool) error {
-	// Special handling for text-expander hook - configure mappings before setup
-	if name == "text-expander" {
-		if err := configureTextExpanderMappings(); err != nil {
-			return fmt.Errorf("failed to configure text expander: %w", err)
-		}
+func installCommand(name string, template *assets.Template, scope string) error {
+	commandsDir, err := config.GetProjectCommandsPath()
+	if scope == config.ScopeUser {
+		commandsDir, err = config.GetUserCommandsPath()
+	}
+	if err != nil {
+		return err
 	}
+	targetPath := filepath.Join(commandsDir, filepath.FromSlash(types.CommandFilePath(name)))
 
+	// Never clobber a command the user has disabled; it must be enabled or removed first
+	if _, err := os.Stat(targetPath + ".disabled"); err == nil {
+		return fmt.Errorf("command '%s' is installed but disabled (%s.disabled). Use 'cchp enable %s' or 'cchp remove %s' first", name, targetPath, name, name)
+	}
 
-	// Special handling for task-notification hook - configure notification settings before setup
-	if name == "task-notification" {
-		if err := configureTaskNotificationSettings(); err != nil {
-			return fmt.Errorf("failed to configure task notification: %w", err)
-		}
+	content, err := template.ReadFile()
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+
+	// Commands are copied as they are, but a broken frontmatter would make Claude Code ignore it
+	if _, err := types.ParseCommand(name, string(content)); err != nil {
+		return err
 	}
 
+	// Namespaced commands need their subdirectories
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return fmt.Errorf("failed to create commands directory: %w", err)
+	}
+	if err := os.WriteFile(targetPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write command file: %w", err)
+	}
+
+	fmt.Printf("Command installed at: %s (run it with %s)\n", targetPath, types.CommandInvocation(name))
+	return nil
+}
+
+func installHook(name string, template *assets.Template, opts installOptions) error {
 	// Read hook template
-	content, err := os.ReadFile(templatePath)
+	content, err := template.ReadFile()
 	if err != nil {
 		return fmt.Errorf("failed to read hook template: %w", err)
 	}
 
 	// Parse hook from YAML
-	hook, err := parseHookFromYAML(content)
+	hook, err := parseHookTemplate(content, types.HookFormatForPath(template.Path))
 	if err != nil {
 		return fmt.Errorf("failed to parse hook template: %w", err)
 	}
 
-	// Execute setup script if present (skip for hooks we handle with Go code)
-	skipSetupHooks := []string{"text-expander", "task-notification"}
-	shouldSkip := false
-	for _, skipHook := range skipSetupHooks {
-		if name == skipHook {
-			shouldSkip = true
-			break
+	// Ask the hook's questions and write its config files before setup runs
+	answers, err := writeHookConfigs(hook, opts.Params, opts.Defaults)
+	if err != nil {
+		return fmt.Errorf("failed to configure %s: %w", name, err)
+	}
+	// Remembered like agent template variables, so sync and upgrade don't ask again
+	if len(answers) > 0 {
+		if err := config.SaveComponentParams(types.LockKey("hook", name, ""), answers); err != nil {
+			return fmt.Errorf("failed to save answers: %w", err)
 		}
 	}
 
-	if hook.Setup != "" && !shouldSkip {
+	// Execute setup script if present
+	if hook.Setup != "" && !opts.SkipSetup {
 		if err := executeSetupScript(hook.Setup); err != nil {
 			return fmt.Errorf("failed to execute setup script: %w", err)
 		}
-	} else if shouldSkip {
-		fmt.Printf("⏭️  Skipping setup script for %s (using Go configuration instead)\n", name)
+	} else if hook.Setup != "" && opts.SkipSetup {
+		fmt.Printf("⏭️  Skipping setup script for %s (already set up)\n", name)
 	}
 
-
 	// Copy associated Python/shell script files if they exist
-	if err := copyHookScriptFiles(name, filepath.Dir(templatePath)); err != nil {
+	if err := copyHookScriptFiles(name, template); err != nil {
 		return fmt.Errorf("failed to copy hook script files: %w", err)
 	}
 
-	// Ensure cross-platform run-python scripts exist
-	if err := ensureCrossPlatformRunPythonScripts(); err != nil {
-		return fmt.Errorf("failed to create cross-platform run-python scripts: %w", err)
+	// Copy the extra files the hook declares
+	if err := copyHookFiles(hook, template); err != nil {
+		return fmt.Errorf("failed to copy hook files: %w", err)
 	}
 
-	// Special handling for text-expander - create Python script and config file
-	if name == "text-expander" {
-		if err := createTextExpanderPythonScript(); err != nil {
-			return fmt.Errorf("failed to create text-expander Python script: %w", err)
-		}
-		if err := createTextExpanderConfig(); err != nil {
-			return fmt.Errorf("failed to create text-expander config: %w", err)
-		}
-	}
-
-
-	// Special handling for task-notification - create Python script and config file
-	if name == "task-notification" {
-		if err := createTaskNotificationPythonScript(); err != nil {
-			return fmt.Errorf("failed to create task-notification Python script: %w", err)
-		}
-		if err := createTaskNotificationConfig(); err != nil {
-			return fmt.Errorf("failed to create task-notification config: %w", err)
-		}
-		if err := copyAudioFiles(); err != nil {
-			return fmt.Errorf("failed to copy audio files: %w", err)
+	// Hooks without a manifest declaring requires.runtime still get run-python when they use it
+	if strings.Contains(hook.Command, "run-python") {
+		if err := ensureRuntimeFiles([]string{"run-python"}); err != nil {
+			return err
 		}
 	}
 
 	// Install hook to Claude settings
-	return installHookToSettings(hook, force)
+	return installHookToSettings(hook, opts.Force)
 }
 
-
-func parseHookFromYAML(content []byte) (*types.Hook, error) {
-	var hook types.Hook
-	
-	if err := yaml.Unmarshal(content, &hook); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+// parseHookTemplate parses and validates a hook template in YAML or JSON (see types.HookFormatForPath)
+func parseHookTemplate(content []byte, format string) (*types.Hook, error) {
+	hook, err := types.ParseHook(content, format)
+	if err != nil {
+		return nil, err
 	}
-	
+
 	// Validate required fields
 	if hook.Name == "" {
 		return nil, fmt.Errorf("hook name is required")
@@ -227,23 +587,26 @@ func parseHookFromYAML(content []byte) (*types.Hook, error) {
 	if hook.Command == "" {
 		return nil, fmt.Errorf("hook command is required")
 	}
+	if err := validateHookDeclarations(hook); err != nil {
+		return nil, err
+	}
 	
 	// Set default values
 	if hook.Timeout == 0 {
 		hook.Timeout = 30 // Default 30 seconds timeout
 	}
 	
-	return &hook, nil
+	return hook, nil
 }
 
 
-func copyHookScriptFiles(hookName, templateDir string) error {
+func copyHookScriptFiles(hookName string, template *assets.Template) error {
 	// Get current working directory
 	wd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
-	
+
 	// Create .claude/hooks directory
 	hooksDir := filepath.Join(wd, ".claude", "hooks")
 	if err := os.MkdirAll(hooksDir, 0755); err != nil {
@@ -251,63 +614,36 @@ func copyHookScriptFiles(hookName, templateDir string) error {
 	}
 
 	// Look for associated script files (.py, .sh, .js, etc.)
-	scriptExtensions := []string{".py", ".sh", ".js", ".ts"}
-	
+	scriptExtensions := []string{".py", ".sh", ".js", ".ts", ".go"}
+
 	for _, ext := range scriptExtensions {
 		scriptName := hookName + ext
-		sourcePath := filepath.Join(templateDir, scriptName)
-		
-		// Check if script file exists
-		if _, err := os.Stat(sourcePath); err == nil {
-			// Copy the script file
-			targetPath := filepath.Join(hooksDir, scriptName)
-			
-			if err := copyFile(sourcePath, targetPath); err != nil {
-				return fmt.Errorf("failed to copy script file %s: %w", scriptName, err)
-			}
-			
-			// Make executable for shell scripts and Python scripts
-			if ext == ".sh" || ext == ".py" {
-				if err := os.Chmod(targetPath, 0755); err != nil {
-					return fmt.Errorf("failed to make script executable: %w", err)
-				}
-			}
-			
-			fmt.Printf("Hook script copied to: %s\n", targetPath)
+
+		// Skip scripts the template doesn't provide
+		content, err := template.ReadSibling(scriptName)
+		if err != nil {
+			continue
 		}
-	}
-	
-	return nil
-}
 
-func copyFile(src, dst string) error {
-	sourceFile, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer sourceFile.Close()
+		// Make executable for shell scripts and Python scripts
+		perm := os.FileMode(0644)
+		if ext == ".sh" || ext == ".py" {
+			perm = 0755
+		}
 
-	destFile, err := os.Create(dst)
-	if err != nil {
-		return err
-	}
-	defer destFile.Close()
+		targetPath := filepath.Join(hooksDir, scriptName)
+		if err := os.WriteFile(targetPath, content, perm); err != nil {
+			return fmt.Errorf("failed to copy script file %s: %w", scriptName, err)
+		}
+		// WriteFile keeps the mode of an existing file
+		if err := os.Chmod(targetPath, perm); err != nil {
+			return fmt.Errorf("failed to make script executable: %w", err)
+		}
 
-	// Use io.Copy instead of WriteTo for compatibility
-	if _, err := sourceFile.Seek(0, 0); err != nil {
-		return err
-	}
-	
-	content, err := os.ReadFile(src)
-	if err != nil {
-		return err
-	}
-	
-	if _, err := destFile.Write(content); err != nil {
-		return err
+		fmt.Printf("Hook script copied to: %s\n", targetPath)
 	}
 
-	return destFile.Sync()
+	return nil
 }
 
 func installHookToSettings(hook *types.Hook, force bool) error {
@@ -316,15 +652,15 @@ func installHookToSettings(hook *types.Hook, force bool) error {
 
 func executeSetupScript(setupScript string) error {
 	fmt.Println("🔧 Executing setup script...")
-	
+
 	var cmd *exec.Cmd
 	var tmpFile *os.File
 	var err error
-	
+
 	if runtime.GOOS == "windows" {
 		// Convert bash script to PowerShell equivalent for Windows
 		powershellScript := convertBashToPowerShell(setupScript)
-		
+
 		// Create a temporary PowerShell script file
 		tmpFile, err = os.CreateTemp("", "claude-helper-setup-*.ps1")
 		if err != nil {
@@ -332,151 +668,52 @@ func executeSetupScript(setupScript string) error {
 		}
 		defer os.Remove(tmpFile.Name())
 		defer tmpFile.Close()
-		
+
 		// Write the PowerShell script to the temp file
 		if _, err := tmpFile.WriteString(powershellScript); err != nil {
 			return fmt.Errorf("failed to write setup script: %w", err)
 		}
 		tmpFile.Close()
-		
+
 		// Find available PowerShell executable
 		powerShellCmd := findPowerShellExecutable()
 		if powerShellCmd == "" {
 			return fmt.Errorf("PowerShell not found. Please install PowerShell or add it to PATH")
 		}
-		
+
 		// Execute the PowerShell script
 		cmd = exec.Command(powerShellCmd, "-ExecutionPolicy", "Bypass", "-File", tmpFile.Name())
 	} else {
-		// Unix/Linux/macOS - use bash
+		bash, err := exec.LookPath("bash")
+		if err != nil {
+			return fmt.Errorf("bash not found. Please install bash or add it to PATH")
+		}
+
 		tmpFile, err = os.CreateTemp("", "claude-helper-setup-*.sh")
 		if err != nil {
 			return fmt.Errorf("failed to create temp script file: %w", err)
 		}
 		defer os.Remove(tmpFile.Name())
 		defer tmpFile.Close()
-		
+
 		// Write the setup script to the temp file
 		if _, err := tmpFile.WriteString(setupScript); err != nil {
 			return fmt.Errorf("failed to write setup script: %w", err)
 		}
 		tmpFile.Close()
-		
-		// Make the script executable
-		if err := os.Chmod(tmpFile.Name(), 0755); err != nil {
-			return fmt.Errorf("failed to make script executable: %w", err)
-		}
-		
-		// Execute the script
-		cmd = exec.Command("/bin/bash", tmpFile.Name())
+
+		cmd = exec.Command(bash, tmpFile.Name())
 	}
-	
+
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Dir, _ = os.Getwd() // Set working directory to current directory
-	
+
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("setup script failed: %w", err)
 	}
-	
-	fmt.Println("✓ Setup script executed successfully")
-	return nil
-}
-
-func configureTextExpanderMappings() error {
-	// Get config path
-	configPath, err := getTextExpanderConfigPath()
-	if err != nil {
-		return fmt.Errorf("failed to get config path: %w", err)
-	}
-
-	// Check if config already exists - if so, skip interactive configuration
-	if _, err := os.Stat(configPath); err == nil {
-		fmt.Println("🔧 Text Expander config already exists, skipping interactive configuration")
-		return nil
-	}
-
-	fmt.Println("🔧 Configuring Text Expander mappings...")
-	fmt.Println("You can create shortcuts that expand to longer text.")
-	fmt.Println("Example: -d -> '详细解释这段代码的功能、实现原理和使用方法'")
-	fmt.Println("Press Enter with empty marker to finish configuration.")
-	fmt.Println()
-
-	// Create new config with default mappings
-	textConfig := &TextExpanderConfig{
-		Mappings: map[string]string{
-			"-d": "该睡觉了",
-			"-z": "该睡觉了", 
-			"-v": "查看详细信息",
-			"-h": "显示帮助信息",
-			"-l": "列出所有项目",
-			"-s": "显示状态信息",
-		},
-		EscapeChar: "\\",
-	}
-
-	// Interactive input
-	reader := bufio.NewReader(os.Stdin)
-	added := 0
-	
-	for {
-		fmt.Print("Enter marker (e.g., -d, -v, --explain): ")
-		marker, err := reader.ReadString('\n')
-		if err != nil {
-			// If we can't read input (e.g., not in a terminal), use default config
-			fmt.Println("\nNo interactive input available, using default configuration")
-			break
-		}
-		
-		marker = strings.TrimSpace(marker)
-		if marker == "" {
-			break // Empty input ends configuration
-		}
-
-		// Validate marker
-		if !isValidMarker(marker) {
-			fmt.Println("❌ Invalid marker. Use format like: -d, -v, --explain, debug")
-			continue
-		}
-
-		fmt.Printf("Enter replacement text for '%s': ", marker)
-		replacement, err := reader.ReadString('\n')
-		if err != nil {
-			return fmt.Errorf("failed to read replacement: %w", err)
-		}
-		
-		replacement = strings.TrimSpace(replacement)
-		if replacement == "" {
-			fmt.Println("❌ Replacement text cannot be empty")
-			continue
-		}
-
-		// Check if marker already exists
-		if existing, exists := textConfig.Mappings[marker]; exists {
-			fmt.Printf("⚠️  Marker '%s' already exists with value: '%s'\n", marker, existing)
-			fmt.Print("Overwrite? (y/N): ")
-			confirm, _ := reader.ReadString('\n')
-			if strings.ToLower(strings.TrimSpace(confirm)) != "y" {
-				continue
-			}
-		}
-
-		textConfig.Mappings[marker] = replacement
-		fmt.Printf("✅ Added mapping: '%s' → '%s'\n", marker, replacement)
-		added++
-		fmt.Println()
-	}
-
-	// Save config to JSON file directly (no more temp files)
-	if err := saveTextExpanderConfig(configPath, textConfig); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
-	}
-	
-	if added > 0 {
-		fmt.Printf("📝 Total new mappings added: %d\n", added)
-	}
-	fmt.Printf("📝 Total mappings configured: %d\n", len(textConfig.Mappings))
 
+	fmt.Println("✓ Setup script executed successfully")
 	return nil
 }
 
@@ -542,202 +779,22 @@ fi
 	return nil
 }
 
-func createTextExpanderPythonScript() error {
-	wd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
-	}
-	
-	hooksDir := filepath.Join(wd, ".claude", "hooks")
-	if err := os.MkdirAll(hooksDir, 0755); err != nil {
-		return fmt.Errorf("failed to create hooks directory: %w", err)
-	}
-
-	pythonScriptPath := filepath.Join(hooksDir, "text-expander.py")
-	
-	// Python script content (extracted from the YAML setup script)
-	pythonContent := `#!/usr/bin/env python3
-import json
-import sys
-import os
-import re
-
-def apply_text_expansions_with_escape(text, mappings, escape_char='\\'):
-    r"""Apply text expansions with support for escape characters.
-    
-    Rules:
-    - \marker -> literal marker (no expansion)
-    - \\marker -> literal \ + expand marker  
-    - \\\marker -> literal \ + literal marker
-    - \\\\marker -> literal \\ + expand marker
-    """
-    if not mappings:
-        return text
-    
-    result = text
-    
-    # Process each mapping
-    for marker, replacement in mappings.items():
-        # Create a pattern that matches the marker with potential escaping
-        # We need to handle sequences of backslashes before the marker
-        pattern = r'(\\*)' + re.escape(marker)
-        
-        def replace_func(match):
-            backslashes = match.group(1)
-            backslash_count = len(backslashes)
-            
-            if backslash_count == 0:
-                # No backslashes, normal expansion
-                return replacement
-            elif backslash_count % 2 == 1:
-                # Odd number of backslashes: last one escapes the marker
-                # Return half the backslashes (rounded down) + literal marker
-                return '\\' * (backslash_count // 2) + marker
-            else:
-                # Even number of backslashes: marker is not escaped
-                # Return half the backslashes + expanded marker
-                return '\\' * (backslash_count // 2) + replacement
-        
-        result = re.sub(pattern, replace_func, result)
-    
-    return result
-
-try:
-    # Read JSON input from stdin
-    input_data = json.load(sys.stdin)
-    
-    # Extract prompt
-    prompt = input_data.get('prompt', '')
-    
-    # Clean the prompt to remove invalid Unicode characters
-    try:
-        # Encode and decode to clean up any invalid UTF-8 characters
-        prompt = prompt.encode('utf-8', errors='ignore').decode('utf-8')
-    except:
-        # If that fails, use ascii encoding as fallback
-        prompt = prompt.encode('ascii', errors='ignore').decode('ascii')
-    
-    if not prompt:
-        sys.exit(0)
-    
-    # Load text expansion config from project .claude/config directory
-    config_file = '.claude/config/text-expander.json'
-    if not os.path.exists(config_file):
-        sys.exit(0)
-    
-    with open(config_file, 'r', encoding='utf-8') as f:
-        config = json.load(f)
-    
-    # Get escape character (default to backslash)
-    escape_char = config.get('escape_char', '\\')
-    mappings = config.get('mappings', {})
-    
-    # Apply text expansions with escape support
-    expanded_prompt = apply_text_expansions_with_escape(prompt, mappings, escape_char)
-    
-    # If prompt changed, add expanded text as additional context
-    if prompt != expanded_prompt:
-        result = {
-            "hookSpecificOutput": {
-                "hookEventName": "UserPromptSubmit",
-                "additionalContext": f"用户的意思是: {expanded_prompt}"
-            }
-        }
-        print(json.dumps(result, ensure_ascii=True), flush=True)
-        sys.exit(0)
-    
-    # No change needed, allow original prompt through
-    sys.exit(0)
-        
-except Exception as e:
-    # On any error, allow original prompt through
-    # For debugging, could log error to a file
-    try:
-        with open('.claude/hook-error.log', 'a', encoding='utf-8', errors='replace') as f:
-            import traceback
-            f.write(f"Text-expander error: {type(e).__name__}: {str(e)}\\n")
-            f.write(f"Traceback: {traceback.format_exc()}\\n")
-    except:
-        pass  # Ignore logging errors
-    sys.exit(0)
-`
-
-	if err := os.WriteFile(pythonScriptPath, []byte(pythonContent), 0755); err != nil {
-		return fmt.Errorf("failed to write Python script: %w", err)
-	}
-
-	fmt.Printf("Text expander Python script created at: %s\n", pythonScriptPath)
-	return nil
-}
-
-func createTextExpanderConfig() error {
-	wd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
-	}
-	
-	configDir := filepath.Join(wd, ".claude", "config")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
-
-	configPath := filepath.Join(configDir, "text-expander.json")
-	
-	// Check if config file already exists - don't overwrite user's interactive configuration!
-	if _, err := os.Stat(configPath); err == nil {
-		fmt.Println("Text expander config already exists, skipping default config creation")
-		return nil
-	}
-	
-	// Only create default config if file doesn't exist
-	config := map[string]interface{}{
-		"mappings": map[string]string{
-			"-d": "该睡觉了",
-			"-z": "该睡觉了",
-			"-v": "查看详细信息",
-			"-h": "显示帮助信息",
-			"-l": "列出所有项目",
-			"-s": "显示状态信息",
-		},
-		"escape_char": "\\",
-	}
-	
-	// Use JSON encoder with proper UTF-8 handling
-	var buf bytes.Buffer
-	encoder := json.NewEncoder(&buf)
-	encoder.SetEscapeHTML(false) // Don't escape HTML characters
-	encoder.SetIndent("", "  ")  // Pretty print with 2-space indentation
-	
-	if err := encoder.Encode(config); err != nil {
-		return fmt.Errorf("failed to encode config: %w", err)
-	}
-	
-	if err := os.WriteFile(configPath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
-	}
-	
-	fmt.Println("Text expander config created with proper UTF-8 encoding!")
-	return nil
-}
-
-// Note: Using shared functions from config.go in the same package
-
 // findPowerShellExecutable tries to find an available PowerShell executable
 func findPowerShellExecutable() string {
 	// Try different PowerShell executables in order of preference
 	powerShellCmds := []string{
-		"pwsh",        // PowerShell 7+ (cross-platform)
-		"powershell",  // Windows PowerShell 5.x
-		"pwsh.exe",    // PowerShell 7+ with .exe extension
+		"pwsh",           // PowerShell 7+ (cross-platform)
+		"powershell",     // Windows PowerShell 5.x
+		"pwsh.exe",       // PowerShell 7+ with .exe extension
 		"powershell.exe", // Windows PowerShell 5.x with .exe extension
 	}
-	
+
 	for _, cmd := range powerShellCmds {
 		if _, err := exec.LookPath(cmd); err == nil {
 			return cmd
 		}
 	}
-	
+
 	return "" // No PowerShell executable found
 }
 
@@ -843,525 +900,3 @@ if (Test-Path ".claude-temp-mappings.txt") {
 Write-Host "Text expander hook installed successfully!"
 `
 }
-
-
-
-
-func copyAudioFiles() error {
-	wd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
-	}
-
-	// Create sounds directory in project
-	soundsDir := filepath.Join(wd, ".claude", "sounds")
-	if err := os.MkdirAll(soundsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create sounds directory: %w", err)
-	}
-
-	// Determine platform-appropriate notification sound
-	platformSound := assets.GetPlatformNotificationSound()
-	var targetFilename string
-	var targetPath string
-
-	// Use platform-appropriate filename
-	switch runtime.GOOS {
-	case "darwin":
-		targetFilename = "notification.aiff"
-	default:
-		targetFilename = "notification.wav"
-	}
-	
-	targetPath = filepath.Join(soundsDir, targetFilename)
-
-	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
-		// First try to get platform-appropriate sound from system
-		if _, err := os.Stat(platformSound); err == nil {
-			content, err := os.ReadFile(platformSound)
-			if err == nil {
-				if err := os.WriteFile(targetPath, content, 0644); err == nil {
-					fmt.Printf("Copied platform system sound to: %s\n", targetPath)
-					return nil
-				}
-			}
-		}
-
-		// Try to get from embedded assets (try both formats)
-		soundFormats := []string{targetFilename}
-		if targetFilename != "notification.aiff" {
-			soundFormats = append(soundFormats, "notification.aiff")
-		}
-		if targetFilename != "notification.wav" {
-			soundFormats = append(soundFormats, "notification.wav")
-		}
-
-		for _, format := range soundFormats {
-			if soundPath, err := assets.GetSoundFilePath(format); err == nil {
-				content, err := os.ReadFile(soundPath)
-				if err == nil {
-					if err := os.WriteFile(targetPath, content, 0644); err == nil {
-						fmt.Printf("Copied embedded sound to: %s\n", targetPath)
-						return nil
-					}
-				}
-			}
-		}
-
-		// Create informational placeholder if all else fails
-		placeholder := fmt.Sprintf("PLACEHOLDER - Cross-platform sound support enabled\nSystem sound: %s\nReplace this file with custom %s if desired", platformSound, targetFilename)
-		if err := os.WriteFile(targetPath, []byte(placeholder), 0644); err != nil {
-			return fmt.Errorf("failed to create sound placeholder: %w", err)
-		}
-		fmt.Printf("Created sound configuration info at: %s\n", targetPath)
-		fmt.Printf("📝 Platform system sound available: %s\n", platformSound)
-	} else {
-		fmt.Printf("Sound file already exists: %s\n", targetPath)
-	}
-	
-	return nil
-}
-
-// configureTaskNotificationSettings handles interactive task notification configuration
-func configureTaskNotificationSettings() error {
-	fmt.Println("🔔 Configuring Task Notification Settings...")
-	fmt.Println("Choose how you want to be notified when Claude completes tasks.")
-	fmt.Println()
-
-	// Check if config already exists
-	configPath, err := getTaskNotificationConfigPath()
-	if err != nil {
-		return fmt.Errorf("failed to get config path: %w", err)
-	}
-
-	if _, err := os.Stat(configPath); err == nil {
-		fmt.Println("🔧 Task notification config already exists, skipping interactive configuration")
-		return nil
-	}
-
-	// Create default notification config
-	notificationConfig := &types.NotificationConfig{
-		NotificationTypes: []string{"desktop"}, // Default to desktop notifications
-		CooldownSecs:      2,
-		Desktop: types.DesktopConfig{
-			Enabled:     true,
-			ShowDetails: false, // Keep desktop notifications simple
-		},
-		Audio: types.AudioConfig{
-			Enabled: false,
-		},
-	}
-
-	// Interactive notification type selection
-	fmt.Println("选择任务完成提醒方式:")
-	fmt.Println("1. 仅桌面通知 (推荐)")
-	fmt.Println("2. 仅音频通知")
-	fmt.Println("3. 桌面通知 + 音频通知")
-	fmt.Println("4. 禁用通知")
-	fmt.Println()
-
-	reader := bufio.NewReader(os.Stdin)
-	for {
-		fmt.Print("请选择 (1-4): ")
-		choice, err := reader.ReadString('\n')
-		if err != nil {
-			// If we can't read input, use default settings
-			fmt.Println("\n无法读取输入，使用默认配置 (桌面通知)")
-			break
-		}
-
-		choice = strings.TrimSpace(choice)
-		switch choice {
-		case "1":
-			notificationConfig.NotificationTypes = []string{"desktop"}
-			notificationConfig.Desktop.Enabled = true
-			notificationConfig.Audio.Enabled = false
-			fmt.Println("✅ 选择了桌面通知")
-		case "2":
-			notificationConfig.NotificationTypes = []string{"audio"}
-			notificationConfig.Desktop.Enabled = false
-			notificationConfig.Audio.Enabled = true
-			fmt.Println("✅ 选择了音频通知 (使用系统默认提示音)")
-		case "3":
-			notificationConfig.NotificationTypes = []string{"desktop", "audio"}
-			notificationConfig.Desktop.Enabled = true
-			notificationConfig.Audio.Enabled = true
-			fmt.Println("✅ 选择了桌面通知 + 音频通知")
-		case "4":
-			notificationConfig.NotificationTypes = []string{}
-			notificationConfig.Desktop.Enabled = false
-			notificationConfig.Audio.Enabled = false
-			fmt.Println("✅ 禁用了所有通知")
-		default:
-			fmt.Println("❌ 无效选择，请输入 1-4")
-			continue
-		}
-		break
-	}
-
-
-	// Save config
-	if err := saveTaskNotificationConfig(configPath, notificationConfig); err != nil {
-		return fmt.Errorf("failed to save notification config: %w", err)
-	}
-
-	fmt.Printf("📝 任务通知配置已保存到: %s\n", configPath)
-	return nil
-}
-
-
-func getTaskNotificationConfigPath() (string, error) {
-	wd, err := os.Getwd()
-	if err != nil {
-		return "", fmt.Errorf("failed to get working directory: %w", err)
-	}
-	return filepath.Join(wd, ".claude", "config", "notification.json"), nil
-}
-
-func saveTaskNotificationConfig(configPath string, config *types.NotificationConfig) error {
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
-
-	// Use JSON encoder with proper formatting
-	var buf bytes.Buffer
-	encoder := json.NewEncoder(&buf)
-	encoder.SetEscapeHTML(false)
-	encoder.SetIndent("", "  ")
-
-	if err := encoder.Encode(config); err != nil {
-		return fmt.Errorf("failed to encode notification config: %w", err)
-	}
-
-	if err := os.WriteFile(configPath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write notification config file: %w", err)
-	}
-
-	return nil
-}
-
-func createTaskNotificationPythonScript() error {
-	wd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
-	}
-
-	hooksDir := filepath.Join(wd, ".claude", "hooks")
-	if err := os.MkdirAll(hooksDir, 0755); err != nil {
-		return fmt.Errorf("failed to create hooks directory: %w", err)
-	}
-
-	pythonScriptPath := filepath.Join(hooksDir, "task-notification.py")
-
-	// The complete Python script with full notification logic
-	pythonContent := `#!/usr/bin/env python3
-import json
-import sys
-import os
-import subprocess
-import time
-import platform
-
-def get_notification_config():
-    """Load notification configuration from config file"""
-    config_file = '.claude/config/notification.json'
-    if not os.path.exists(config_file):
-        # Try legacy audio config
-        legacy_config_file = '.claude/config/audio-notification.json'
-        if os.path.exists(legacy_config_file):
-            return migrate_legacy_config(legacy_config_file)
-        return None
-        
-    try:
-        with open(config_file, 'r', encoding='utf-8') as f:
-            return json.load(f)
-    except Exception as e:
-        return None
-
-def migrate_legacy_config(legacy_path):
-    """Migrate legacy audio config to new notification config"""
-    try:
-        with open(legacy_path, 'r', encoding='utf-8') as f:
-            legacy_config = json.load(f)
-        
-        # Convert to new format
-        new_config = {
-            "notification_types": ["audio"] if legacy_config.get("enabled", True) else [],
-            "cooldown_seconds": legacy_config.get("cooldown_seconds", 2),
-            "desktop": {
-                "enabled": False,
-                "show_details": True
-            },
-            "audio": {
-                "enabled": legacy_config.get("enabled", True),
-                "success_sound": legacy_config.get("success_sound", "success.wav"),
-                "error_sound": legacy_config.get("error_sound", "error.wav"),
-                "default_sound": legacy_config.get("default_sound", "complete.wav"),
-                "volume": legacy_config.get("volume", 70)
-            }
-        }
-        return new_config
-    except Exception as e:
-        return None
-
-def should_send_notification(config):
-    """Check if we should send a notification based on cooldown"""
-    if not config.get('notification_types'):
-        return False
-        
-    cooldown = config.get('cooldown_seconds', 2)
-    if cooldown <= 0:
-        return True
-        
-    # Check last notification time
-    last_file = '.claude/last-notification-time'
-    if os.path.exists(last_file):
-        try:
-            with open(last_file, 'r') as f:
-                last_time = float(f.read().strip())
-            if time.time() - last_time < cooldown:
-                return False
-        except:
-            pass
-            
-    # Update last notification time
-    try:
-        with open(last_file, 'w') as f:
-            f.write(str(time.time()))
-    except:
-        pass
-        
-    return True
-
-def analyze_tool_result(tool_result):
-    """Analyze tool result to determine success/failure and extract info"""
-    success = True
-    tool_name = "任务"
-    details = ""
-    
-    try:
-        result_str = str(tool_result).lower()
-        
-        # Check for error indicators
-        error_keywords = ['error', 'failed', 'exception', 'timeout', 'denied', 'not found']
-        for keyword in error_keywords:
-            if keyword in result_str:
-                success = False
-                break
-        
-        # Try to extract tool information
-        if isinstance(tool_result, dict):
-            if 'tool_name' in tool_result:
-                tool_name = tool_result['tool_name']
-            elif 'command' in tool_result:
-                tool_name = f"命令执行"
-            elif 'file' in result_str:
-                tool_name = f"文件操作"
-        
-    except Exception as e:
-        pass
-    
-    return success, tool_name, details
-
-def send_desktop_notification(title, message, message_type="info"):
-    """Send desktop notification across platforms"""
-    try:
-        system = platform.system().lower()
-        
-        if system == 'darwin':  # macOS
-            script = f'display notification "{message}" with title "{title}"'
-            subprocess.run(['osascript', '-e', script], 
-                         check=False, 
-                         stdout=subprocess.DEVNULL, 
-                         stderr=subprocess.DEVNULL)
-        elif system == 'linux':
-            # Try notify-send first
-            try:
-                subprocess.run(['notify-send', title, message], 
-                             check=True,
-                             stdout=subprocess.DEVNULL, 
-                             stderr=subprocess.DEVNULL)
-            except (subprocess.CalledProcessError, FileNotFoundError):
-                # Try zenity as fallback
-                try:
-                    notification_text = f"{title}\\n{message}"
-                    subprocess.run(['zenity', '--notification', f'--text={notification_text}'], 
-                                 check=True,
-                                 stdout=subprocess.DEVNULL, 
-                                 stderr=subprocess.DEVNULL)
-                except (subprocess.CalledProcessError, FileNotFoundError):
-                    return False
-        else:  # Windows
-            # Use PowerShell balloon notification
-            ps_script = f"""
-                Add-Type -AssemblyName System.Windows.Forms
-                $balloon = New-Object System.Windows.Forms.NotifyIcon
-                $balloon.Icon = [System.Drawing.SystemIcons]::Information
-                $balloon.BalloonTipTitle = "{title}"
-                $balloon.BalloonTipText = "{message}"
-                $balloon.Visible = $true
-                $balloon.ShowBalloonTip(10000)
-                Start-Sleep -Seconds 5
-                $balloon.Dispose()
-            """
-            subprocess.run(['powershell', '-Command', ps_script], 
-                         check=False,
-                         stdout=subprocess.DEVNULL, 
-                         stderr=subprocess.DEVNULL)
-        return True
-    except Exception as e:
-        return False
-
-def get_notification_sound_path():
-    """Get the path to the notification sound file"""
-    # Check in project .claude/sounds directory first
-    project_sound = os.path.join('.claude', 'sounds', 'notification.aiff')
-    if os.path.exists(project_sound):
-        return project_sound
-        
-    # Check relative to hooks directory for embedded sounds
-    script_dir = os.path.dirname(os.path.abspath(__file__))
-    embedded_sound = os.path.join(script_dir, '..', 'sounds', 'notification.aiff')
-    if os.path.exists(embedded_sound):
-        return embedded_sound
-    
-    # Fallback to system sound if embedded sound not found
-    system_sound = '/System/Library/Sounds/Glass.aiff'
-    if os.path.exists(system_sound):
-        return system_sound
-        
-    return None
-
-def send_audio_notification(config, success):
-    """Send audio notification"""
-    audio_config = config.get('audio', {})
-    if not audio_config.get('enabled', False):
-        return False
-    
-    sound_path = get_notification_sound_path()
-    if not sound_path:
-        return False
-    
-    try:
-        system = platform.system().lower()
-        
-        if system == 'darwin':  # macOS
-            subprocess.run(['afplay', sound_path], 
-                         check=False,
-                         stdout=subprocess.DEVNULL, 
-                         stderr=subprocess.DEVNULL)
-        elif system == 'linux':
-            # TODO: Add Linux system sound support
-            pass
-        elif system == 'windows':
-            # TODO: Add Windows system sound support
-            pass
-        else:
-            return False
-        return True
-    except Exception as e:
-        return False
-
-def main():
-    try:
-        # Load configuration
-        config = get_notification_config()
-        if not config:
-            sys.exit(0)  # No config, exit silently
-            
-        # Check if notifications should be sent
-        if not should_send_notification(config):
-            sys.exit(0)
-            
-        # Read tool use data from stdin
-        try:
-            input_data = json.load(sys.stdin)
-        except:
-            sys.exit(0)
-            
-        # Analyze the tool result
-        success, tool_name, details = analyze_tool_result(input_data)
-        
-        # Prepare notification content
-        if success:
-            title = "Claude Helper - 任务完成"
-            message = f"✅ {tool_name} 操作完成"
-            message_type = "success"
-        else:
-            title = "Claude Helper - 任务失败"  
-            message = f"❌ {tool_name} 操作失败"
-            message_type = "error"
-        
-        # Send notifications based on configured types
-        notification_types = config.get('notification_types', [])
-        
-        # Send desktop notification
-        if 'desktop' in notification_types:
-            desktop_config = config.get('desktop', {})
-            if desktop_config.get('enabled', False):
-                send_desktop_notification(title, message, message_type)
-        
-        # Send audio notification
-        if 'audio' in notification_types:
-            send_audio_notification(config, success)
-            
-    except Exception as e:
-        # On any error, fail silently
-        try:
-            with open('.claude/notification-error.log', 'a', encoding='utf-8', errors='replace') as f:
-                import traceback
-                f.write(f"Task notification error: {type(e).__name__}: {str(e)}\\n")
-                f.write(f"Traceback: {traceback.format_exc()}\\n")
-        except:
-            pass  # Ignore logging errors
-    
-    sys.exit(0)
-
-if __name__ == '__main__':
-    main()
-`
-
-	if err := os.WriteFile(pythonScriptPath, []byte(pythonContent), 0755); err != nil {
-		return fmt.Errorf("failed to write Python script: %w", err)
-	}
-
-	fmt.Printf("Task notification Python script created at: %s\n", pythonScriptPath)
-	return nil
-}
-
-func createTaskNotificationConfig() error {
-	wd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
-	}
-
-	configDir := filepath.Join(wd, ".claude", "config")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
-
-	configPath := filepath.Join(configDir, "notification.json")
-
-	// Check if config already exists
-	if _, err := os.Stat(configPath); err == nil {
-		fmt.Println("Task notification config already exists, skipping default config creation")
-		return nil
-	}
-
-	// Create default config - desktop + audio with system sound
-	config := &types.NotificationConfig{
-		NotificationTypes: []string{"desktop", "audio"},
-		CooldownSecs:      2,
-		Desktop: types.DesktopConfig{
-			Enabled:     true,
-			ShowDetails: false,
-		},
-		Audio: types.AudioConfig{
-			Enabled: true,
-		},
-	}
-
-	return saveTaskNotificationConfig(configPath, config)
-}
-
-
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/internal/manifest"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+// verifyTemplateIntegrity checks a template's files against its manifest checksums,
+// and the manifest's signature when its source has trusted keys, before anything
+// is copied or run. Built-in templates are part of the binary and aren't checked.
+func verifyTemplateIntegrity(template *assets.Template, allowUnverified bool) error {
+	if template.Origin == assets.OriginBuiltin {
+		return nil
+	}
+
+	source, err := config.FindTemplateSource(template.Origin)
+	if err != nil {
+		return err
+	}
+
+	componentManifest, err := manifest.Load(template)
+	if err != nil {
+		return err
+	}
+
+	// Sources with trusted keys only accept components from a signed manifest
+	if source != nil && len(source.TrustedKeys) > 0 {
+		manifestPath, err := manifest.Locate(template)
+		if err != nil {
+			return err
+		}
+		if manifestPath == "" || componentManifest == nil {
+			return fmt.Errorf("'%s' from source '%s' has no manifest to verify its signature", template.Name, source.Name)
+		}
+		if err := manifest.VerifySignature(template.FS, manifestPath, source.TrustedKeys); err != nil {
+			return fmt.Errorf("'%s' from source '%s' failed signature verification: %w", template.Name, source.Name, err)
+		}
+		fmt.Printf("✓ Manifest signature verified for source '%s'\n", source.Name)
+	}
+
+	if componentManifest == nil || len(componentManifest.Checksums) == 0 {
+		return handleUnverifiedTemplate(template, source, allowUnverified)
+	}
+
+	problems, err := manifest.VerifyChecksums(template, componentManifest.Checksums)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Printf("❌ %s\n", problem)
+		}
+		return fmt.Errorf("'%s' failed checksum verification; its files may have been tampered with", template.Name)
+	}
+
+	fmt.Printf("✓ Checksums verified for '%s'\n", template.Name)
+	return nil
+}
+
+// handleUnverifiedTemplate decides whether a template without checksums may be installed.
+// Components from git sources are refused unless explicitly allowed.
+func handleUnverifiedTemplate(template *assets.Template, source *types.TemplateSource, allowUnverified bool) error {
+	if source == nil || source.Type != types.SourceTypeGit {
+		return nil
+	}
+	if len(source.TrustedKeys) > 0 {
+		return fmt.Errorf("'%s' from source '%s' has no checksums in its signed manifest", template.Name, source.Name)
+	}
+	if !allowUnverified {
+		return fmt.Errorf("'%s' from git source '%s' has no checksums and can't be verified. Use --allow-unverified to install it anyway", template.Name, source.Name)
+	}
+
+	fmt.Printf("⚠️  Installing unverified component '%s' from source '%s'\n", template.Name, source.Name)
+	return nil
+}
@@ -3,19 +3,19 @@ package cli
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/zxj777/claude-helper/internal/assets"
 	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/pkg/types"
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available templates and installed components",
-	Long:  `Display all available agent and hook templates, showing their status.`,
+	Long:  `Display all available agent, hook, slash command, MCP server, permission preset and bundle templates, showing their status.`,
 	RunE:  listComponents,
 }
 
@@ -23,6 +23,10 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().BoolP("agents", "a", false, "Show only agents")
 	listCmd.Flags().BoolP("hooks", "k", false, "Show only hooks")
+	listCmd.Flags().BoolP("commands", "c", false, "Show only slash commands")
+	listCmd.Flags().BoolP("mcp", "m", false, "Show only MCP servers")
+	listCmd.Flags().BoolP("permissions", "p", false, "Show only permission presets")
+	listCmd.Flags().BoolP("bundles", "b", false, "Show only bundles")
 	listCmd.Flags().BoolP("installed", "i", false, "Show only installed components")
 }
 
@@ -30,6 +34,7 @@ type Component struct {
 	Name        string
 	Type        string
 	Description string
+	Source      string
 	Status      string
 }
 
@@ -37,77 +42,211 @@ func listComponents(cmd *cobra.Command, args []string) error {
 	// Get command flags
 	showAgents, _ := cmd.Flags().GetBool("agents")
 	showHooks, _ := cmd.Flags().GetBool("hooks")
+	showCommands, _ := cmd.Flags().GetBool("commands")
+	showMCP, _ := cmd.Flags().GetBool("mcp")
+	showPermissions, _ := cmd.Flags().GetBool("permissions")
+	showBundles, _ := cmd.Flags().GetBool("bundles")
 	showInstalled, _ := cmd.Flags().GetBool("installed")
-
-	// Get templates directory from assets
-	templatesDir, err := assets.GetTemplatesDir()
-	if err != nil {
-		return fmt.Errorf("failed to get templates directory: %w", err)
-	}
+	showAll := !showAgents && !showHooks && !showCommands && !showMCP && !showPermissions && !showBundles
 
 	fmt.Println("Scanning for templates...")
 
 	var components []Component
 
-	// Scan agents directory
-	agentsDir := filepath.Join(templatesDir, "agents")
-	if !showHooks { // Only scan agents if not specifically showing hooks
-		err := filepath.Walk(agentsDir, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil // Continue walking even if some paths fail
-			}
-			
-			if !info.IsDir() && strings.HasSuffix(strings.ToLower(path), ".md") {
-				name := strings.TrimSuffix(info.Name(), ".md")
-				
-				// Check if agent is installed
-				status := "Available"
-				if installed, err := config.IsAgentInstalled(name); err == nil && installed {
-					status = "Installed"
+	// Scan agent templates (user and project templates shadow built-ins)
+	if showAll || showAgents {
+		templates, err := assets.ListTemplates("agent")
+		if err != nil {
+			fmt.Printf("Warning: failed to scan agent templates: %v\n", err)
+		}
+		for _, template := range templates {
+			// Check if agent is installed
+			status := "Available"
+			if agentFile, err := config.FindAgentFile(template.Name); err == nil && agentFile != nil {
+				status = "Installed"
+				if agentFile.Disabled {
+					status = "Disabled"
+				}
+				if agentFile.Scope == config.ScopeUser {
+					status += " (user)"
 				}
-				
-				components = append(components, Component{
-					Name:        name,
-					Type:        "agent",
-					Description: "Claude agent template", // TODO: Parse from file
-					Status:      status,
-				})
 			}
-			return nil
-		})
+
+			components = append(components, Component{
+				Name:        template.Name,
+				Type:        "agent",
+				Description: "Claude agent template", // TODO: Parse from file
+				Source:      template.Origin,
+				Status:      status,
+			})
+		}
+	}
+
+	// Scan hook templates
+	if showAll || showHooks {
+		templates, err := assets.ListTemplates("hook")
+		if err != nil {
+			fmt.Printf("Warning: failed to scan hook templates: %v\n", err)
+		}
+		for _, template := range templates {
+			// Check if hook is installed
+			status := "Available"
+			if installed, err := config.IsHookInstalled(template.Name); err == nil && installed {
+				status = "Installed"
+			}
+
+			components = append(components, Component{
+				Name:        template.Name,
+				Type:        "hook",
+				Description: "Claude hook template", // TODO: Parse from file
+				Source:      template.Origin,
+				Status:      status,
+			})
+		}
+	}
+
+	// Scan slash command templates
+	if showAll || showCommands {
+		templates, err := assets.ListTemplates("command")
 		if err != nil {
-			fmt.Printf("Warning: failed to scan agents directory: %v\n", err)
+			fmt.Printf("Warning: failed to scan command templates: %v\n", err)
+		}
+		for _, template := range templates {
+			status := "Available"
+			if commandFile, err := config.FindCommandFile(template.Name); err == nil && commandFile != nil {
+				status = "Installed"
+				if commandFile.Disabled {
+					status = "Disabled"
+				}
+				if commandFile.Scope == config.ScopeUser {
+					status += " (user)"
+				}
+			}
+
+			description := "Slash command " + types.CommandInvocation(template.Name)
+			if content, err := template.ReadFile(); err == nil {
+				if command, err := types.ParseCommand(template.Name, string(content)); err == nil && command.Frontmatter.Description != "" {
+					description = command.Frontmatter.Description
+				}
+			}
+
+			components = append(components, Component{
+				Name:        template.Name,
+				Type:        "command",
+				Description: description,
+				Source:      template.Origin,
+				Status:      status,
+			})
 		}
 	}
 
-	// Scan hooks directory  
-	hooksDir := filepath.Join(templatesDir, "hooks")
-	if !showAgents { // Only scan hooks if not specifically showing agents
-		err := filepath.Walk(hooksDir, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil // Continue walking even if some paths fail
+	// Scan MCP server templates
+	if showAll || showMCP {
+		templates, err := assets.ListTemplates("mcp")
+		if err != nil {
+			fmt.Printf("Warning: failed to scan MCP server templates: %v\n", err)
+		}
+		for _, template := range templates {
+			status := "Available"
+			if _, scope, err := config.FindMCPServer(template.Name); err == nil && scope != "" {
+				status = "Installed"
+				if scope == config.ScopeUser {
+					status += " (user)"
+				}
 			}
-			
-			if !info.IsDir() && strings.HasSuffix(strings.ToLower(path), ".yaml") {
-				name := strings.TrimSuffix(info.Name(), ".yaml")
-				
-				// Check if hook is installed
-				status := "Available"
-				if installed, err := config.IsHookInstalled(name); err == nil && installed {
+
+			description := "MCP server"
+			if content, err := template.ReadFile(); err == nil {
+				if server, err := parseMCPTemplate(template.Name, content); err == nil && server.Description != "" {
+					description = server.Description
+				}
+			}
+
+			components = append(components, Component{
+				Name:        template.Name,
+				Type:        "mcp",
+				Description: description,
+				Source:      template.Origin,
+				Status:      status,
+			})
+		}
+	}
+
+	// Scan permission preset templates
+	if showAll || showPermissions {
+		templates, err := assets.ListTemplates("permission")
+		if err != nil {
+			fmt.Printf("Warning: failed to scan permission preset templates: %v\n", err)
+		}
+		lock, err := config.LoadLockfile()
+		if err != nil {
+			fmt.Printf("Warning: failed to read lockfile: %v\n", err)
+		}
+		for _, template := range templates {
+			// Preset rules are ordinary rules once installed; the lockfile records the preset
+			status := "Available"
+			if lock != nil {
+				var scopes []string
+				for _, scope := range config.SettingsScopes {
+					if _, ok := lock.Components[types.LockKey("permission", template.Name, scope)]; ok {
+						scopes = append(scopes, scope)
+					}
+				}
+				if len(scopes) > 0 {
 					status = "Installed"
+					if len(scopes) > 1 || scopes[0] != config.ScopeProject {
+						status += " (" + strings.Join(scopes, ", ") + ")"
+					}
 				}
-				
-				components = append(components, Component{
-					Name:        name,
-					Type:        "hook", 
-					Description: "Claude hook template", // TODO: Parse from file
-					Status:      status,
-				})
 			}
-			return nil
-		})
+
+			description := "Permission preset"
+			if content, err := template.ReadFile(); err == nil {
+				if preset, err := parsePermissionPreset(template.Name, content); err == nil && preset.Description != "" {
+					description = preset.Description
+				}
+			}
+
+			components = append(components, Component{
+				Name:        template.Name,
+				Type:        "permission",
+				Description: description,
+				Source:      template.Origin,
+				Status:      status,
+			})
+		}
+	}
+
+	// Scan bundle templates
+	if showAll || showBundles {
+		templates, err := assets.ListTemplates("bundle")
 		if err != nil {
-			fmt.Printf("Warning: failed to scan hooks directory: %v\n", err)
+			fmt.Printf("Warning: failed to scan bundle templates: %v\n", err)
+		}
+		lock, err := config.LoadLockfile()
+		if err != nil {
+			fmt.Printf("Warning: failed to read lockfile: %v\n", err)
+		}
+		for _, template := range templates {
+			status := "Available"
+			if lock != nil {
+				if _, ok := lock.Bundles[template.Name]; ok {
+					status = "Installed"
+				}
+			}
+
+			description := "Component bundle"
+			if bundle, err := loadBundle(template.Name); err == nil && bundle.Description != "" {
+				description = bundle.Description
+			}
+
+			components = append(components, Component{
+				Name:        template.Name,
+				Type:        "bundle",
+				Description: description,
+				Source:      template.Origin,
+				Status:      status,
+			})
 		}
 	}
 
@@ -115,7 +254,7 @@ func listComponents(cmd *cobra.Command, args []string) error {
 	if showInstalled {
 		var installedComponents []Component
 		for _, comp := range components {
-			if comp.Status == "Installed" {
+			if comp.Status != "Available" {
 				installedComponents = append(installedComponents, comp)
 			}
 		}
@@ -129,12 +268,12 @@ func listComponents(cmd *cobra.Command, args []string) error {
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "NAME\tTYPE\tDESCRIPTION\tSTATUS")
-	fmt.Fprintln(w, "----\t----\t-----------\t------")
-	
+	fmt.Fprintln(w, "NAME\tTYPE\tDESCRIPTION\tSOURCE\tSTATUS")
+	fmt.Fprintln(w, "----\t----\t-----------\t------\t------")
+
 	for _, comp := range components {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", 
-			comp.Name, comp.Type, comp.Description, comp.Status)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			comp.Name, comp.Type, comp.Description, comp.Source, comp.Status)
 	}
 	
 	w.Flush()
@@ -0,0 +1,590 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Manage MCP servers",
+	Long: `Manage the MCP servers Claude Code connects to.
+
+Project servers are kept in .mcp.json, which is shared through version control;
+user servers in ~/.claude.json. Only the mcpServers entries cchp writes are
+changed; every other key in these files is kept as it is.
+
+Use ${VAR} or ${VAR:-default} for secrets so they stay in your environment
+instead of the file. Claude Code expands them when it starts the server.`,
+}
+
+var mcpListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured MCP servers",
+	RunE:  listMCPServers,
+}
+
+var mcpAddCmd = &cobra.Command{
+	Use:   "add <name> [command-or-url] [args...]",
+	Short: "Add an MCP server",
+	Long: `Add an MCP server. With only a name, the MCP server template of that name is
+installed, as with 'cchp install'. Otherwise the server is configured directly
+from a command (stdio) or a URL (http, or sse with --transport sse).
+
+Flags go before the name; everything after the command is passed to the server.
+
+Examples:
+  cchp mcp add github
+  cchp mcp add --scope user github
+  cchp mcp add -e ROOT='${PWD}' files npx -y @modelcontextprotocol/server-filesystem .
+  cchp mcp add -H 'Authorization: Bearer ${SENTRY_TOKEN}' sentry https://mcp.sentry.dev/mcp`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: addMCPServer,
+}
+
+var mcpRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove an MCP server",
+	Args:  cobra.ExactArgs(1),
+	RunE:  removeMCPServerCommand,
+}
+
+var mcpTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Start a stdio MCP server and check its initialize handshake",
+	Long: `Start a stdio MCP server the way Claude Code would, with its ${VAR}
+placeholders expanded from the environment, perform the initialize handshake
+and list the tools it offers.
+
+Configured servers are tested first; otherwise the MCP server template of that
+name is used, so a template can be tried before it is installed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: testMCPServer,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+	mcpCmd.AddCommand(mcpListCmd)
+	mcpCmd.AddCommand(mcpAddCmd)
+	mcpCmd.AddCommand(mcpRemoveCmd)
+	mcpCmd.AddCommand(mcpTestCmd)
+
+	mcpListCmd.Flags().String("scope", "", "Only list servers of one scope: project or user")
+
+	// Server commands take their own flags, e.g. npx -y
+	mcpAddCmd.Flags().SetInterspersed(false)
+	mcpAddCmd.Flags().String("scope", config.ScopeProject, "Where to add the server: project (.mcp.json) or user (~/.claude.json)")
+	mcpAddCmd.Flags().StringP("transport", "t", "", "Server transport: stdio, sse or http (default stdio, or http for a URL)")
+	mcpAddCmd.Flags().StringArrayP("env", "e", []string{}, "Environment variable for a stdio server (KEY=VALUE, repeatable)")
+	mcpAddCmd.Flags().StringArrayP("header", "H", []string{}, "HTTP header for an sse or http server ('Name: value', repeatable)")
+	mcpAddCmd.Flags().BoolP("force", "f", false, "Replace a server with the same name")
+
+	mcpRemoveCmd.Flags().String("scope", "", "Scope to remove the server from (defaults to where it is configured)")
+
+	mcpTestCmd.Flags().Duration("timeout", 30*time.Second, "How long to wait for the server to start and answer")
+}
+
+// parseMCPTemplate parses and validates an MCP server template in YAML or JSON
+func parseMCPTemplate(name string, content []byte) (*types.MCPServer, error) {
+	var server types.MCPServer
+	if err := yaml.Unmarshal(content, &server); err != nil {
+		return nil, fmt.Errorf("failed to parse MCP server: %w", err)
+	}
+	server.Name = name
+	if err := server.Validate(); err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+func installMCPServer(name string, template *assets.Template, scope string) error {
+	content, err := template.ReadFile()
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+	server, err := parseMCPTemplate(name, content)
+	if err != nil {
+		return err
+	}
+	return saveMCPServer(server, scope)
+}
+
+// saveMCPServer writes a server to the scope's config, warning about secrets and unset variables
+func saveMCPServer(server *types.MCPServer, scope string) error {
+	if scope == "" {
+		scope = config.ScopeProject
+	}
+	// Written explicitly so the entry doesn't depend on Claude Code's default
+	server.Type = server.Transport()
+
+	if err := config.SetMCPServer(scope, server); err != nil {
+		return err
+	}
+	path, err := config.GetMCPConfigPath(scope)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("MCP server configured in: %s\n", path)
+
+	if scope == config.ScopeProject {
+		for _, name := range server.LiteralSecrets() {
+			fmt.Printf("⚠️  %s looks like a secret but is stored as is in .mcp.json, which is usually committed. Use ${%s} instead\n", name, name)
+		}
+	}
+	for _, name := range server.Placeholders(true) {
+		if value, ok := os.LookupEnv(name); !ok || value == "" {
+			fmt.Printf("⚠️  %s is not set; set it in your environment before Claude Code starts the server\n", name)
+		}
+	}
+	return nil
+}
+
+func listMCPServers(cmd *cobra.Command, args []string) error {
+	scopeFlag, _ := cmd.Flags().GetString("scope")
+	scopes := []string{config.ScopeProject, config.ScopeUser}
+	if scopeFlag != "" {
+		if scopeFlag != config.ScopeProject && scopeFlag != config.ScopeUser {
+			return fmt.Errorf("invalid scope '%s' (use project or user)", scopeFlag)
+		}
+		scopes = []string{scopeFlag}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	found := 0
+	for _, scope := range scopes {
+		servers, err := config.LoadMCPServers(scope)
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(servers))
+		for name := range servers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if found == 0 {
+				fmt.Fprintln(w, "NAME\tSCOPE\tTYPE\tTARGET\tMISSING ENV")
+				fmt.Fprintln(w, "----\t-----\t----\t------\t-----------")
+			}
+			found++
+
+			server := servers[name]
+			var missing []string
+			for _, variable := range server.Placeholders(true) {
+				if value, ok := os.LookupEnv(variable); !ok || value == "" {
+					missing = append(missing, variable)
+				}
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, scope, server.Transport(), server.Target(), strings.Join(missing, ", "))
+		}
+	}
+
+	if found == 0 {
+		fmt.Println("No MCP servers configured. Add one with 'cchp mcp add' or see 'cchp list --mcp'.")
+		return nil
+	}
+	return w.Flush()
+}
+
+func addMCPServer(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	scope, _ := cmd.Flags().GetString("scope")
+	transport, _ := cmd.Flags().GetString("transport")
+	envValues, _ := cmd.Flags().GetStringArray("env")
+	headerValues, _ := cmd.Flags().GetStringArray("header")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if scope != config.ScopeProject && scope != config.ScopeUser {
+		return fmt.Errorf("invalid scope '%s' (use project or user)", scope)
+	}
+	// Templates may be qualified with a source, e.g. team/jira
+	sourceName, serverName := assets.SplitQualifiedName(name)
+	if !isValidComponentName(serverName) {
+		return fmt.Errorf("invalid server name '%s'. Use lowercase letters, numbers, and hyphens only", serverName)
+	}
+
+	// A name alone installs the template, which also records it in the lockfile
+	if len(args) == 1 {
+		if transport != "" || len(envValues) > 0 || len(headerValues) > 0 {
+			return fmt.Errorf("--transport, --env and --header need a command or URL")
+		}
+		if _, err := assets.FindTemplate("mcp", name); err != nil {
+			return fmt.Errorf("no MCP server template named '%s'; pass a command or URL to add a server directly", name)
+		}
+		return installComponentFromTemplate(name, installOptions{Force: force, Scope: scope})
+	}
+
+	if sourceName != "" {
+		return fmt.Errorf("a template source can only be given without a command or URL")
+	}
+	server := &types.MCPServer{Name: name, Type: transport}
+	target := args[1]
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		server.URL = target
+		if len(args) > 2 {
+			return fmt.Errorf("unexpected arguments after the URL: %s", strings.Join(args[2:], " "))
+		}
+	} else {
+		server.Command = target
+		server.Args = args[2:]
+	}
+
+	var err error
+	if server.Env, err = parseKeyValues(envValues, "="); err != nil {
+		return fmt.Errorf("invalid --env: %w", err)
+	}
+	if server.Headers, err = parseKeyValues(headerValues, ":"); err != nil {
+		return fmt.Errorf("invalid --header: %w", err)
+	}
+	if err := server.Validate(); err != nil {
+		return err
+	}
+
+	if !force {
+		servers, err := config.LoadMCPServers(scope)
+		if err != nil {
+			return err
+		}
+		if _, ok := servers[name]; ok {
+			return fmt.Errorf("MCP server '%s' is already configured in the %s scope. Use --force to replace it", name, scope)
+		}
+	}
+
+	if err := saveMCPServer(server, scope); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Added %s MCP server '%s'\n", server.Transport(), name)
+	return nil
+}
+
+// parseKeyValues parses KEY<sep>VALUE flags into a map
+func parseKeyValues(values []string, sep string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]string)
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, sep)
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("'%s' is not in the form KEY%sVALUE", value, sep)
+		}
+		if sep != "=" {
+			val = strings.TrimSpace(val)
+		}
+		result[key] = val
+	}
+	return result, nil
+}
+
+func removeMCPServerCommand(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	scope, _ := cmd.Flags().GetString("scope")
+
+	if scope == "" {
+		server, foundScope, err := config.FindMCPServer(name)
+		if err != nil {
+			return err
+		}
+		if server == nil {
+			return fmt.Errorf("MCP server '%s' is not configured", name)
+		}
+		scope = foundScope
+	} else if scope != config.ScopeProject && scope != config.ScopeUser {
+		return fmt.Errorf("invalid scope '%s' (use project or user)", scope)
+	}
+
+	removed, err := config.RemoveMCPServer(scope, name)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("MCP server '%s' is not configured in the %s scope", name, scope)
+	}
+
+	// Drop the lockfile entry when it was installed from a template into this scope
+	if err := config.RemoveLockedComponent(types.LockKey("mcp", name, scope)); err != nil {
+		fmt.Printf("Warning: failed to update lockfile: %v\n", err)
+	}
+
+	fmt.Printf("✓ Removed MCP server '%s' (%s scope)\n", name, scope)
+	return nil
+}
+
+// removeMCPServer removes a server from a scope, for 'cchp remove'
+func removeMCPServer(name, scope string) error {
+	removed, err := config.RemoveMCPServer(scope, name)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("MCP server '%s' is not configured in the %s scope", name, scope)
+	}
+	return nil
+}
+
+func testMCPServer(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	server, scope, err := config.FindMCPServer(name)
+	if err != nil {
+		return err
+	}
+	if server != nil {
+		fmt.Printf("Testing MCP server '%s' from the %s scope\n", name, scope)
+	} else {
+		template, err := assets.FindTemplate("mcp", name)
+		if err != nil {
+			return fmt.Errorf("MCP server '%s' is not configured and there is no template of that name", name)
+		}
+		content, err := template.ReadFile()
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		if server, err = parseMCPTemplate(name, content); err != nil {
+			return err
+		}
+		fmt.Printf("Testing MCP server template '%s' (%s)\n", name, template.Location())
+	}
+
+	if server.Transport() != types.MCPTransportStdio {
+		return fmt.Errorf("only stdio servers can be tested; '%s' is an %s server at %s", name, server.Transport(), server.URL)
+	}
+
+	expanded, missing := server.Expand(os.LookupEnv)
+	if len(missing) > 0 {
+		return fmt.Errorf("set %s in your environment first", strings.Join(missing, ", "))
+	}
+
+	fmt.Printf("🔌 Starting: %s\n", expanded.Target())
+	result, err := runMCPHandshake(expanded, timeout)
+	if err != nil {
+		if result != nil && result.Stderr != "" {
+			fmt.Println("Server stderr:")
+			fmt.Println(result.Stderr)
+		}
+		return fmt.Errorf("MCP server '%s' failed the handshake: %w", name, err)
+	}
+
+	fmt.Printf("✓ Initialized %s %s (protocol %s) in %s\n", result.ServerName, result.ServerVersion, result.ProtocolVersion, result.Elapsed.Round(time.Millisecond))
+	if len(result.Capabilities) > 0 {
+		fmt.Printf("  Capabilities: %s\n", strings.Join(result.Capabilities, ", "))
+	}
+	if result.Tools != nil {
+		fmt.Printf("✓ %d tool(s)", len(result.Tools))
+		if len(result.Tools) > 0 {
+			fmt.Printf(": %s", strings.Join(result.Tools, ", "))
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// mcpProtocolVersion is the MCP protocol version cchp asks for; servers may answer with another
+const mcpProtocolVersion = "2025-06-18"
+
+// mcpHandshake is what a server reported during the initialize handshake
+type mcpHandshake struct {
+	ServerName      string
+	ServerVersion   string
+	ProtocolVersion string
+	Capabilities    []string
+	Tools           []string // nil when the server has no tools capability
+	Elapsed         time.Duration
+	Stderr          string
+}
+
+// mcpMessage is a JSON-RPC message from the server
+type mcpMessage struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// runMCPHandshake starts a stdio server, initializes it and lists its tools
+func runMCPHandshake(server *types.MCPServer, timeout time.Duration) (*mcpHandshake, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, server.Command, server.Args...)
+	cmd.Env = os.Environ()
+	for key, value := range server.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", server.Command, err)
+	}
+	result := &mcpHandshake{}
+	defer func() {
+		stdin.Close()
+		done := make(chan struct{})
+		go func() {
+			cmd.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			cmd.Process.Kill()
+			<-done
+		}
+		result.Stderr = lastLines(stderr.String(), 10)
+	}()
+
+	// Servers write one JSON-RPC message per line; anything else is ignored
+	messages := make(chan mcpMessage)
+	go func() {
+		defer close(messages)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			var message mcpMessage
+			if json.Unmarshal(scanner.Bytes(), &message) == nil {
+				select {
+				case messages <- message:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	send := func(message map[string]interface{}) error {
+		message["jsonrpc"] = "2.0"
+		data, err := json.Marshal(message)
+		if err != nil {
+			return err
+		}
+		_, err = stdin.Write(append(data, '\n'))
+		return err
+	}
+	receive := func(id int) (json.RawMessage, error) {
+		for {
+			select {
+			case message, ok := <-messages:
+				if !ok {
+					return nil, fmt.Errorf("server exited before answering")
+				}
+				if message.ID == nil || *message.ID != id || message.Method != "" {
+					continue // Notifications and requests from the server
+				}
+				if message.Error != nil {
+					return nil, fmt.Errorf("server returned error %d: %s", message.Error.Code, message.Error.Message)
+				}
+				return message.Result, nil
+			case <-ctx.Done():
+				return nil, fmt.Errorf("no answer within %s", timeout)
+			}
+		}
+	}
+
+	err = send(map[string]interface{}{
+		"id":     1,
+		"method": "initialize",
+		"params": map[string]interface{}{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]interface{}{},
+			"clientInfo":      map[string]string{"name": "cchp", "version": Version},
+		},
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to send initialize: %w", err)
+	}
+	raw, err := receive(1)
+	if err != nil {
+		return result, err
+	}
+	result.Elapsed = time.Since(start)
+
+	var initialized struct {
+		ProtocolVersion string                     `json:"protocolVersion"`
+		Capabilities    map[string]json.RawMessage `json:"capabilities"`
+		ServerInfo      struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(raw, &initialized); err != nil {
+		return result, fmt.Errorf("invalid initialize result: %w", err)
+	}
+	if initialized.ProtocolVersion == "" {
+		return result, fmt.Errorf("initialize result has no protocolVersion")
+	}
+	result.ServerName = initialized.ServerInfo.Name
+	result.ServerVersion = initialized.ServerInfo.Version
+	result.ProtocolVersion = initialized.ProtocolVersion
+	for capability := range initialized.Capabilities {
+		result.Capabilities = append(result.Capabilities, capability)
+	}
+	sort.Strings(result.Capabilities)
+
+	if err := send(map[string]interface{}{"method": "notifications/initialized"}); err != nil {
+		return result, fmt.Errorf("failed to send initialized: %w", err)
+	}
+	if _, ok := initialized.Capabilities["tools"]; !ok {
+		return result, nil
+	}
+
+	if err := send(map[string]interface{}{"id": 2, "method": "tools/list"}); err != nil {
+		return result, fmt.Errorf("failed to list tools: %w", err)
+	}
+	raw, err = receive(2)
+	if err != nil {
+		return result, fmt.Errorf("failed to list tools: %w", err)
+	}
+	var tools struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(raw, &tools); err != nil {
+		return result, fmt.Errorf("invalid tools/list result: %w", err)
+	}
+	result.Tools = []string{}
+	for _, tool := range tools.Tools {
+		result.Tools = append(result.Tools, tool.Name)
+	}
+	return result, nil
+}
+
+// lastLines returns the last n lines of text
+func lastLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
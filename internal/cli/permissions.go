@@ -0,0 +1,708 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+var permissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Manage permission rules",
+	Long: `Manage the permission rules in Claude Code's settings files:
+
+  user      ~/.claude/settings.json
+  project   .claude/settings.json, shared through version control
+  local     .claude/settings.local.json, personal and not committed
+
+Rules name a tool, optionally with a specifier: Bash(npm run test:*) matches
+commands starting with "npm run test", Read(./secrets/**) matches files by
+gitignore pattern and WebFetch(domain:example.com) matches a domain. Whatever
+file they are in, deny rules win over ask rules, which win over allow rules.
+
+Permission presets are templates of rules, installed with 'cchp permissions
+preset <name>' or 'cchp install <name>'; see 'cchp list --permissions'.`,
+}
+
+var permissionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List permission rules and the problems between them",
+	Long: `List the permission rules of every settings scope, then report invalid
+rules, rules listed more than once and rules that never apply because a rule
+with a stronger behavior covers them.`,
+	RunE: listPermissions,
+}
+
+var permissionsRemoveCmd = &cobra.Command{
+	Use:   "remove <rule>...",
+	Short: "Remove permission rules",
+	Long:  `Remove permission rules from one scope, or from every scope that has them.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  removePermissionRules,
+}
+
+var permissionsModeCmd = &cobra.Command{
+	Use:   "mode [mode]",
+	Short: "Show or set the default permission mode",
+	Long: `Show the defaultMode of every scope, or set it for one scope to one of:
+default, acceptEdits, plan or bypassPermissions.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: setPermissionMode,
+}
+
+var permissionsPresetCmd = &cobra.Command{
+	Use:   "preset <name>",
+	Short: "Install a permission preset into a settings scope",
+	Long: `Install a permission preset template, adding its rules to one settings scope.
+Rules the scope already gives another behavior are kept as they are.
+
+Examples:
+  cchp permissions preset protect-secrets
+  cchp permissions preset --scope local node-dev`,
+	Args: cobra.ExactArgs(1),
+	RunE: installPermissionPresetCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(permissionsCmd)
+	permissionsCmd.AddCommand(permissionsListCmd)
+	permissionsListCmd.Flags().String("scope", "", "Only list one scope: user, project or local")
+
+	for _, behavior := range []string{types.PermissionAllow, types.PermissionDeny, types.PermissionAsk} {
+		behaviorCmd := &cobra.Command{
+			Use:   behavior + " <rule>...",
+			Short: permissionBehaviorShort[behavior],
+			Args:  cobra.MinimumNArgs(1),
+			RunE:  addPermissionRules(behavior),
+		}
+		behaviorCmd.Flags().String("scope", config.ScopeProject, "Settings scope: user, project or local")
+		permissionsCmd.AddCommand(behaviorCmd)
+	}
+
+	permissionsCmd.AddCommand(permissionsRemoveCmd)
+	permissionsRemoveCmd.Flags().String("scope", "", "Settings scope (defaults to every scope that has the rule)")
+
+	permissionsCmd.AddCommand(permissionsModeCmd)
+	permissionsModeCmd.Flags().String("scope", config.ScopeProject, "Settings scope: user, project or local")
+
+	permissionsCmd.AddCommand(permissionsPresetCmd)
+	permissionsPresetCmd.Flags().String("scope", config.ScopeProject, "Settings scope: user, project or local")
+	permissionsPresetCmd.Flags().BoolP("force", "f", false, "Install even if every rule is already there")
+}
+
+var permissionBehaviorShort = map[string]string{
+	types.PermissionAllow: "Allow tool uses without asking",
+	types.PermissionDeny:  "Refuse tool uses",
+	types.PermissionAsk:   "Always ask before tool uses",
+}
+
+// permissionLayer is one settings scope and its file
+type permissionLayer struct {
+	Scope    string
+	Path     string
+	Settings *config.ClaudeSettings
+}
+
+// permissions returns the layer's permissions, creating them when missing
+func (l *permissionLayer) permissions() *types.Permissions {
+	if l.Settings.Permissions == nil {
+		l.Settings.Permissions = &types.Permissions{}
+	}
+	return l.Settings.Permissions
+}
+
+// loadPermissionLayer reads one scope's settings
+func loadPermissionLayer(scope string) (*permissionLayer, error) {
+	path, err := config.GetScopedSettingsPath(scope)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := config.LoadSettings(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s settings: %w", scope, err)
+	}
+	return &permissionLayer{Scope: scope, Path: path, Settings: settings}, nil
+}
+
+// loadPermissionLayers reads every scope, lowest precedence first
+func loadPermissionLayers() ([]*permissionLayer, error) {
+	var layers []*permissionLayer
+	for _, scope := range config.SettingsScopes {
+		layer, err := loadPermissionLayer(scope)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+// permissionEntry is one rule in one list of one scope
+type permissionEntry struct {
+	Scope    string
+	Behavior string
+	Rule     string
+	Parsed   types.PermissionRule
+	Err      error
+}
+
+func (e permissionEntry) String() string {
+	return fmt.Sprintf("%s %s (%s)", e.Behavior, e.Rule, e.Scope)
+}
+
+// permissionEntries flattens the rules of every layer
+func permissionEntries(layers []*permissionLayer) []permissionEntry {
+	var entries []permissionEntry
+	for _, layer := range layers {
+		if layer.Settings.Permissions == nil {
+			continue
+		}
+		for _, behavior := range types.PermissionBehaviors {
+			for _, rule := range *layer.Settings.Permissions.Rules(behavior) {
+				parsed, err := types.ParsePermissionRule(rule)
+				entries = append(entries, permissionEntry{Scope: layer.Scope, Behavior: behavior, Rule: rule, Parsed: parsed, Err: err})
+			}
+		}
+	}
+	return entries
+}
+
+// permissionPrecedence ranks behaviors; lower wins
+func permissionPrecedence(behavior string) int {
+	for i, b := range types.PermissionBehaviors {
+		if b == behavior {
+			return i
+		}
+	}
+	return len(types.PermissionBehaviors)
+}
+
+// checkPermissions reports invalid rules, duplicates and rules that a rule with
+// a stronger behavior makes pointless, across all layers
+func checkPermissions(layers []*permissionLayer) []lintIssue {
+	var issues []lintIssue
+	entries := permissionEntries(layers)
+
+	for _, entry := range entries {
+		if entry.Err != nil {
+			issues = append(issues, lintIssue{Error: true, Rules: []string{entry.Rule},
+				Message: fmt.Sprintf("%s %s (%s) is invalid: %v", entry.Behavior, entry.Rule, entry.Scope, entry.Err)})
+		}
+	}
+
+	for i, a := range entries {
+		if a.Err != nil {
+			continue
+		}
+		for _, b := range entries[i+1:] {
+			if b.Err != nil {
+				continue
+			}
+			rules := []string{a.Rule, b.Rule}
+			switch {
+			case a.Rule == b.Rule && a.Behavior == b.Behavior:
+				where := fmt.Sprintf("both the %s and %s settings", a.Scope, b.Scope)
+				if a.Scope == b.Scope {
+					where = fmt.Sprintf("the %s settings twice", a.Scope)
+				}
+				issues = append(issues, lintIssue{Rules: rules,
+					Message: fmt.Sprintf("%s %s is in %s", a.Behavior, a.Rule, where)})
+			case a.Behavior != b.Behavior:
+				strong, weak := a, b
+				if permissionPrecedence(b.Behavior) < permissionPrecedence(a.Behavior) {
+					strong, weak = b, a
+				}
+				if strong.Parsed.Covers(weak.Parsed) {
+					issues = append(issues, lintIssue{Rules: rules,
+						Message: fmt.Sprintf("%s never applies: %s wins", weak, strong)})
+				}
+			}
+		}
+	}
+
+	for _, layer := range layers {
+		if layer.Settings.Permissions != nil && layer.Settings.Permissions.DefaultMode != "" && !isPermissionMode(layer.Settings.Permissions.DefaultMode) {
+			issues = append(issues, lintIssue{Error: true,
+				Message: fmt.Sprintf("defaultMode '%s' (%s) is not one of %s", layer.Settings.Permissions.DefaultMode, layer.Scope, strings.Join(types.PermissionModes, ", "))})
+		}
+	}
+	return issues
+}
+
+func isPermissionMode(mode string) bool {
+	for _, m := range types.PermissionModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+func printLintIssue(issue lintIssue) {
+	if issue.Error {
+		fmt.Printf("  ❌ %s\n", issue.Message)
+	} else {
+		fmt.Printf("  ⚠️  %s\n", issue.Message)
+	}
+}
+
+// printRelatedPermissionIssues prints the issues involving any of the rules
+func printRelatedPermissionIssues(layers []*permissionLayer, rules []string) {
+	related := make(map[string]bool)
+	for _, rule := range rules {
+		related[rule] = true
+	}
+	for _, issue := range checkPermissions(layers) {
+		for _, rule := range issue.Rules {
+			if related[rule] {
+				printLintIssue(issue)
+				break
+			}
+		}
+	}
+}
+
+// permissionScopes validates a --scope flag, returning every scope when it is empty
+func permissionScopes(scope string) ([]string, error) {
+	if scope == "" {
+		return config.SettingsScopes, nil
+	}
+	if _, err := config.GetScopedSettingsPath(scope); err != nil {
+		return nil, err
+	}
+	return []string{scope}, nil
+}
+
+func listPermissions(cmd *cobra.Command, args []string) error {
+	scope, _ := cmd.Flags().GetString("scope")
+	scopes, err := permissionScopes(scope)
+	if err != nil {
+		return err
+	}
+
+	layers, err := loadPermissionLayers()
+	if err != nil {
+		return err
+	}
+
+	for _, layer := range layers {
+		if len(scopes) == 1 && layer.Scope != scopes[0] {
+			continue
+		}
+		fmt.Printf("%s (%s):\n", layer.Scope, layer.Path)
+		permissions := layer.Settings.Permissions
+		if permissions == nil || len(permissions.Allow)+len(permissions.Deny)+len(permissions.Ask) == 0 && permissions.DefaultMode == "" {
+			fmt.Println("  No permission rules")
+			continue
+		}
+		if permissions.DefaultMode != "" {
+			fmt.Printf("  defaultMode: %s\n", permissions.DefaultMode)
+		}
+		for _, behavior := range types.PermissionBehaviors {
+			for _, rule := range *permissions.Rules(behavior) {
+				fmt.Printf("  %-5s  %s\n", behavior, rule)
+			}
+		}
+	}
+
+	issues := checkPermissions(layers)
+	if len(issues) > 0 {
+		fmt.Println("\nProblems:")
+		for _, issue := range issues {
+			printLintIssue(issue)
+		}
+	}
+	return nil
+}
+
+func addPermissionRules(behavior string) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		scope, _ := cmd.Flags().GetString("scope")
+
+		// Nothing is written unless every rule is valid
+		for _, rule := range args {
+			if _, err := types.ParsePermissionRule(rule); err != nil {
+				return fmt.Errorf("invalid rule: %w", err)
+			}
+		}
+
+		layers, err := loadPermissionLayers()
+		if err != nil {
+			return err
+		}
+		var layer *permissionLayer
+		for _, l := range layers {
+			if l.Scope == scope {
+				layer = l
+			}
+		}
+		if layer == nil {
+			_, err := config.GetScopedSettingsPath(scope)
+			return err
+		}
+
+		permissions := layer.permissions()
+		changed := false
+		for _, rule := range args {
+			if containsString(*permissions.Rules(behavior), rule) {
+				fmt.Printf("⏭️  %s is already in %s (%s)\n", rule, behavior, scope)
+				continue
+			}
+			// A rule has one behavior per scope; adding it elsewhere moves it
+			for _, other := range types.PermissionBehaviors {
+				if other != behavior && containsString(*permissions.Rules(other), rule) {
+					*permissions.Rules(other) = removeString(*permissions.Rules(other), rule)
+					fmt.Printf("Moving %s from %s to %s\n", rule, other, behavior)
+				}
+			}
+			*permissions.Rules(behavior) = append(*permissions.Rules(behavior), rule)
+			fmt.Printf("✓ Added %s %s (%s)\n", behavior, rule, scope)
+			changed = true
+		}
+
+		if changed {
+			if err := config.SaveSettings(layer.Path, layer.Settings); err != nil {
+				return err
+			}
+			fmt.Printf("💾 Settings saved to: %s\n", layer.Path)
+		}
+		printRelatedPermissionIssues(layers, args)
+		return nil
+	}
+}
+
+func removePermissionRules(cmd *cobra.Command, args []string) error {
+	scope, _ := cmd.Flags().GetString("scope")
+	scopes, err := permissionScopes(scope)
+	if err != nil {
+		return err
+	}
+
+	removed := make(map[string]bool)
+	for _, scope := range scopes {
+		layer, err := loadPermissionLayer(scope)
+		if err != nil {
+			return err
+		}
+		if layer.Settings.Permissions == nil {
+			continue
+		}
+
+		changed := false
+		for _, rule := range args {
+			for _, behavior := range types.PermissionBehaviors {
+				rules := layer.Settings.Permissions.Rules(behavior)
+				if containsString(*rules, rule) {
+					*rules = removeString(*rules, rule)
+					fmt.Printf("✓ Removed %s %s (%s)\n", behavior, rule, scope)
+					removed[rule] = true
+					changed = true
+				}
+			}
+		}
+		if changed {
+			if err := config.SaveSettings(layer.Path, layer.Settings); err != nil {
+				return err
+			}
+		}
+	}
+
+	var missing []string
+	for _, rule := range args {
+		if !removed[rule] {
+			missing = append(missing, rule)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("no such rule: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func setPermissionMode(cmd *cobra.Command, args []string) error {
+	scope, _ := cmd.Flags().GetString("scope")
+
+	if len(args) == 0 {
+		layers, err := loadPermissionLayers()
+		if err != nil {
+			return err
+		}
+		for _, layer := range layers {
+			mode := "(not set)"
+			if layer.Settings.Permissions != nil && layer.Settings.Permissions.DefaultMode != "" {
+				mode = layer.Settings.Permissions.DefaultMode
+			}
+			fmt.Printf("%-8s %s\n", layer.Scope, mode)
+		}
+		return nil
+	}
+
+	mode := args[0]
+	if !isPermissionMode(mode) {
+		return fmt.Errorf("invalid mode '%s' (use %s)", mode, strings.Join(types.PermissionModes, ", "))
+	}
+	layer, err := loadPermissionLayer(scope)
+	if err != nil {
+		return err
+	}
+	layer.permissions().DefaultMode = mode
+	if err := config.SaveSettings(layer.Path, layer.Settings); err != nil {
+		return err
+	}
+	if mode == "bypassPermissions" {
+		fmt.Println("⚠️  bypassPermissions skips every permission prompt; only use it in a sandbox")
+	}
+	fmt.Printf("✓ Set defaultMode to %s (%s)\n", mode, scope)
+	return nil
+}
+
+func installPermissionPresetCommand(cmd *cobra.Command, args []string) error {
+	scope, _ := cmd.Flags().GetString("scope")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if _, err := config.GetScopedSettingsPath(scope); err != nil {
+		return err
+	}
+	if _, err := assets.FindTemplate("permission", args[0]); err != nil {
+		return fmt.Errorf("no permission preset named '%s'", args[0])
+	}
+	return installComponentFromTemplate(args[0], installOptions{Force: force, Scope: scope})
+}
+
+// parsePermissionPreset parses and validates a permission preset template
+func parsePermissionPreset(name string, content []byte) (*types.PermissionPreset, error) {
+	var preset types.PermissionPreset
+	if err := yaml.Unmarshal(content, &preset); err != nil {
+		return nil, fmt.Errorf("failed to parse permission preset: %w", err)
+	}
+	preset.Name = name
+	for _, behavior := range types.PermissionBehaviors {
+		for _, rule := range preset.Rules(behavior) {
+			if _, err := types.ParsePermissionRule(rule); err != nil {
+				return nil, fmt.Errorf("invalid %s rule: %w", behavior, err)
+			}
+		}
+	}
+	if preset.DefaultMode != "" && !isPermissionMode(preset.DefaultMode) {
+		return nil, fmt.Errorf("invalid defaultMode '%s' (use %s)", preset.DefaultMode, strings.Join(types.PermissionModes, ", "))
+	}
+	return &preset, nil
+}
+
+// loadPermissionPreset finds and parses a permission preset template
+func loadPermissionPreset(templateRef string) (*types.PermissionPreset, error) {
+	template, err := assets.FindTemplate("permission", templateRef)
+	if err != nil {
+		return nil, err
+	}
+	content, err := template.ReadFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	_, name := assets.SplitQualifiedName(templateRef)
+	return parsePermissionPreset(name, content)
+}
+
+// installPermissionPreset adds a preset's rules to a settings file. Rules the
+// file already gives another behavior are left as the user chose. The rules it
+// adds are recorded in the lockfile so removing the preset takes out only those.
+func installPermissionPreset(name string, template *assets.Template, scope string) error {
+	content, err := template.ReadFile()
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+	preset, err := parsePermissionPreset(name, content)
+	if err != nil {
+		return err
+	}
+
+	layers, err := loadPermissionLayers()
+	if err != nil {
+		return err
+	}
+	var layer *permissionLayer
+	for _, l := range layers {
+		if l.Scope == scopeOrProject(scope) {
+			layer = l
+		}
+	}
+	permissions := layer.permissions()
+
+	// A reinstall adds nothing new, so keep what earlier installs in this scope added
+	recorded := &types.Permissions{}
+	lock, err := config.LoadLockfile()
+	if err != nil {
+		return err
+	}
+	lockKey := types.LockKey("permission", name, scope)
+	if entry, ok := lock.Components[lockKey]; ok && entry.Added != nil {
+		recorded = entry.Added
+	}
+
+	var added []string
+	for _, behavior := range types.PermissionBehaviors {
+		for _, rule := range preset.Rules(behavior) {
+			if containsString(*permissions.Rules(behavior), rule) {
+				continue
+			}
+			conflict := ""
+			for _, other := range types.PermissionBehaviors {
+				if other != behavior && containsString(*permissions.Rules(other), rule) {
+					conflict = other
+				}
+			}
+			if conflict != "" {
+				fmt.Printf("⚠️  Keeping %s %s; the preset would %s it\n", conflict, rule, behavior)
+				continue
+			}
+			*permissions.Rules(behavior) = append(*permissions.Rules(behavior), rule)
+			added = append(added, rule)
+			if !containsString(*recorded.Rules(behavior), rule) {
+				*recorded.Rules(behavior) = append(*recorded.Rules(behavior), rule)
+			}
+		}
+	}
+	if preset.DefaultMode != "" {
+		if permissions.DefaultMode == "" {
+			permissions.DefaultMode = preset.DefaultMode
+			recorded.DefaultMode = preset.DefaultMode
+		} else if permissions.DefaultMode != preset.DefaultMode {
+			fmt.Printf("⚠️  Keeping defaultMode %s; the preset would set %s\n", permissions.DefaultMode, preset.DefaultMode)
+		}
+	}
+
+	if err := config.SaveSettings(layer.Path, layer.Settings); err != nil {
+		return err
+	}
+	if err := config.SavePresetAdditions(lockKey, recorded); err != nil {
+		fmt.Printf("Warning: failed to update lockfile: %v\n", err)
+	}
+	fmt.Printf("Added %d permission rule(s) to: %s\n", len(added), layer.Path)
+	printRelatedPermissionIssues(layers, added)
+	return nil
+}
+
+// isPermissionPresetInstalled reports whether a scope has every rule of a preset.
+// Rules and a defaultMode the user set differently count, as installing keeps them.
+func isPermissionPresetInstalled(name, scope string) (bool, error) {
+	preset, err := loadPermissionPreset(name)
+	if err != nil {
+		return false, err
+	}
+	layer, err := loadPermissionLayer(scopeOrProject(scope))
+	if err != nil {
+		return false, err
+	}
+	permissions := layer.permissions()
+	for _, behavior := range types.PermissionBehaviors {
+		for _, rule := range preset.Rules(behavior) {
+			if !hasPermissionRule(permissions, rule) {
+				return false, nil
+			}
+		}
+	}
+	return preset.DefaultMode == "" || permissions.DefaultMode != "", nil
+}
+
+// hasPermissionRule reports whether permissions has a rule under any behavior
+func hasPermissionRule(permissions *types.Permissions, rule string) bool {
+	for _, behavior := range types.PermissionBehaviors {
+		if containsString(*permissions.Rules(behavior), rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// removePermissionPreset removes the rules a preset added, keeping those
+// another installed preset in the same scope also has
+func removePermissionPreset(name, scope string) error {
+	lock, err := config.LoadLockfile()
+	if err != nil {
+		return err
+	}
+	scope = scopeOrProject(scope)
+
+	// Presets missing from the lockfile, e.g. when sync removes them, or locked
+	// before added rules were recorded, remove every rule of their template
+	lockKey := types.LockKey("permission", name, scope)
+	entry, ok := lock.Components[lockKey]
+	rules := entry.Added
+	if !ok || rules == nil {
+		templateRef := name
+		if ok {
+			templateRef = entry.Template
+		}
+		preset, err := loadPermissionPreset(templateRef)
+		if err != nil {
+			return err
+		}
+		rules = &types.Permissions{Allow: preset.Allow, Deny: preset.Deny, Ask: preset.Ask, DefaultMode: preset.DefaultMode}
+	}
+
+	keep := make(map[string]bool)
+	keepMode := false
+	for otherKey, other := range lock.Components {
+		if otherKey == lockKey || other.Type != "permission" || scopeOrProject(other.Scope) != scope {
+			continue
+		}
+		otherPreset, err := loadPermissionPreset(other.Template)
+		if err != nil {
+			continue
+		}
+		for _, behavior := range types.PermissionBehaviors {
+			for _, rule := range otherPreset.Rules(behavior) {
+				keep[behavior+" "+rule] = true
+			}
+		}
+		keepMode = keepMode || otherPreset.DefaultMode == rules.DefaultMode
+	}
+
+	layer, err := loadPermissionLayer(scope)
+	if err != nil {
+		return err
+	}
+	permissions := layer.permissions()
+	removed := 0
+	for _, behavior := range types.PermissionBehaviors {
+		for _, rule := range *rules.Rules(behavior) {
+			if !keep[behavior+" "+rule] && containsString(*permissions.Rules(behavior), rule) {
+				*permissions.Rules(behavior) = removeString(*permissions.Rules(behavior), rule)
+				removed++
+			}
+		}
+	}
+	if rules.DefaultMode != "" && permissions.DefaultMode == rules.DefaultMode && !keepMode {
+		permissions.DefaultMode = ""
+	}
+
+	if err := config.SaveSettings(layer.Path, layer.Settings); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d permission rule(s) from: %s\n", removed, layer.Path)
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
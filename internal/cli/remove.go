@@ -9,12 +9,22 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/pkg/types"
 )
 
 var removeCmd = &cobra.Command{
 	Use:   "remove <component-name>",
 	Short: "Remove a component from Claude Code",
-	Long:  `Remove an installed agent or hook from your Claude Code configuration.`,
+	Long: `Remove an installed agent, hook, slash command or MCP server from your Claude Code configuration.
+
+Use bundle:<name> to remove the components a bundle installed. Components that
+were already installed before the bundle are left in place.
+
+Agents, slash commands and MCP servers are removed from the project unless
+--scope user is given; personal copies are never touched otherwise.
+
+Components that other installed components require can't be removed unless
+--cascade is passed, which removes those components as well.`,
 	Args:  cobra.ExactArgs(1),
 	RunE:  removeComponent,
 }
@@ -22,64 +32,199 @@ var removeCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(removeCmd)
 	removeCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
+	removeCmd.Flags().Bool("cascade", false, "Also remove installed components that require this one")
+	removeCmd.Flags().String("scope", config.ScopeProject, "Scope to remove from: project, user, or local for permission presets")
 }
 
 func removeComponent(cmd *cobra.Command, args []string) error {
 	componentName := args[0]
 	skipConfirm, _ := cmd.Flags().GetBool("yes")
+	cascade, _ := cmd.Flags().GetBool("cascade")
+	scope, _ := cmd.Flags().GetString("scope")
+
+	// bundle:<name> removes the components the bundle installed
+	if bundleName, ok := strings.CutPrefix(componentName, bundlePrefix); ok {
+		return removeBundle(bundleName, skipConfirm, cascade)
+	}
+
+	if _, err := config.GetScopedSettingsPath(scope); err != nil {
+		return err
+	}
 
 	fmt.Printf("Removing component: %s\n", componentName)
 
-	// Check what type of component this is and if it's installed
-	componentType, err := detectInstalledComponentType(componentName)
+	// Check what type of component this is and if it's installed in the scope
+	componentType, err := detectInstalledComponentType(componentName, scope)
 	if err != nil {
-		return fmt.Errorf("component '%s' not found or not installed: %w", componentName, err)
+		for _, other := range config.SettingsScopes {
+			if other == scope {
+				continue
+			}
+			if otherType, err := detectInstalledComponentType(componentName, other); err == nil {
+				return fmt.Errorf("%s '%s' is only installed in the %s scope. Use --scope %s to remove it", otherType, componentName, other, other)
+			}
+		}
+		return fmt.Errorf("component '%s' not found or not installed in the %s scope: %w", componentName, scope, err)
 	}
 
 	fmt.Printf("Found installed %s: %s\n", componentType, componentName)
 
+	// Refuse to break components that still need this one
+	componentKey := types.LockKey(componentType, componentName, scope)
+	ordered, dependents, err := collectRemovals([]string{componentKey})
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		if !cascade {
+			return fmt.Errorf("component '%s' is required by %s. Use --cascade to remove them as well", componentName, strings.Join(dependents, ", "))
+		}
+		fmt.Printf("Also removing components that require it: %s\n", strings.Join(dependents, ", "))
+	}
+
 	// Confirm removal (unless -y flag is used)
 	if !skipConfirm {
-		if !confirmRemoval(componentName, componentType) {
+		if !confirmRemoval(componentName, componentType, componentLocation(componentName, componentType, scope)) {
 			fmt.Println("Removal cancelled.")
 			return nil
 		}
 	}
 
-	// Remove based on component type
+	for _, key := range ordered {
+		if key == componentKey {
+			continue
+		}
+		dependentType, dependentName, dependentScope := types.SplitLockKey(key)
+		if err := removeInstalledComponent(dependentName, dependentType, dependentScope); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Removed %s '%s'\n", dependentType, dependentName)
+	}
+
+	if err := removeInstalledComponent(componentName, componentType, scope); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Successfully removed %s '%s'\n", componentType, componentName)
+	return nil
+}
+
+// removeInstalledComponent removes a component from a scope and drops it from the lockfile
+func removeInstalledComponent(name, componentType, scope string) error {
+	scope = scopeOrProject(scope)
+	var err error
 	switch componentType {
 	case "agent":
-		err = removeAgent(componentName)
+		err = removeAgent(name, scope)
 	case "hook":
-		err = removeHook(componentName)
+		err = removeHook(name)
+	case "command":
+		err = removeCommand(name, scope)
+	case "mcp":
+		err = removeMCPServer(name, scope)
+	case "permission":
+		err = removePermissionPreset(name, scope)
 	default:
 		return fmt.Errorf("unsupported component type: %s", componentType)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to remove %s '%s': %w", componentType, componentName, err)
+		return fmt.Errorf("failed to remove %s '%s': %w", componentType, name, err)
 	}
 
-	fmt.Printf("✓ Successfully removed %s '%s'\n", componentType, componentName)
+	if err := config.RemoveLockedComponent(types.LockKey(componentType, name, scope)); err != nil {
+		fmt.Printf("Warning: failed to update lockfile: %v\n", err)
+	}
 	return nil
 }
 
-func detectInstalledComponentType(name string) (string, error) {
-	// Check if it's an installed agent
-	if installed, err := config.IsAgentInstalled(name); err == nil && installed {
+// detectInstalledComponentType finds what kind of component is installed under a
+// name, in one scope or, when scope is empty, in any scope
+func detectInstalledComponentType(name, scope string) (string, error) {
+	// Check if it's an installed agent, enabled or disabled
+	findAgent := config.FindAgentFile
+	if scope != "" {
+		findAgent = func(name string) (*config.ComponentFile, error) { return config.FindAgentFileInScope(name, scope) }
+	}
+	if agentFile, err := findAgent(name); err == nil && agentFile != nil {
 		return "agent", nil
 	}
 
-	// Check if it's an installed hook
-	if installed, err := config.IsHookInstalled(name); err == nil && installed {
-		return "hook", nil
+	// Check if it's an installed hook; hooks only live in the project
+	if scopeOrProject(scope) == config.ScopeProject {
+		if installed, err := config.IsHookInstalled(name); err == nil && installed {
+			return "hook", nil
+		}
+	}
+
+	// Check if it's an installed slash command, enabled or disabled
+	findCommand := config.FindCommandFile
+	if scope != "" {
+		findCommand = func(name string) (*config.ComponentFile, error) { return config.FindCommandFileInScope(name, scope) }
+	}
+	if commandFile, err := findCommand(name); err == nil && commandFile != nil {
+		return "command", nil
+	}
+
+	// Check if it's a configured MCP server
+	for _, mcpScope := range []string{config.ScopeProject, config.ScopeUser} {
+		if scope != "" && scope != mcpScope {
+			continue
+		}
+		if servers, err := config.LoadMCPServers(mcpScope); err == nil && servers[name] != nil {
+			return "mcp", nil
+		}
+	}
+
+	// Permission presets are rules in settings files; only the lockfile knows them
+	if lock, err := config.LoadLockfile(); err == nil {
+		for _, presetScope := range config.SettingsScopes {
+			if scope != "" && scope != presetScope {
+				continue
+			}
+			if _, ok := lock.Components[types.LockKey("permission", name, presetScope)]; ok {
+				return "permission", nil
+			}
+		}
 	}
 
 	return "", fmt.Errorf("component not installed")
 }
 
-func confirmRemoval(name, componentType string) bool {
+// scopeOrProject maps the empty scope lockfile entries use for the project to ScopeProject
+func scopeOrProject(scope string) string {
+	if scope == "" {
+		return config.ScopeProject
+	}
+	return scope
+}
+
+// componentLocation returns the file a component is removed from, for the confirmation prompt
+func componentLocation(name, componentType, scope string) string {
+	scope = scopeOrProject(scope)
+	var path string
+	switch componentType {
+	case "agent":
+		if agentFile, err := config.FindAgentFileInScope(name, scope); err == nil && agentFile != nil {
+			path = agentFile.Path
+		}
+	case "command":
+		if commandFile, err := config.FindCommandFileInScope(name, scope); err == nil && commandFile != nil {
+			path = commandFile.Path
+		}
+	case "mcp":
+		path, _ = config.GetMCPConfigPath(scope)
+	case "hook", "permission":
+		path, _ = config.GetScopedSettingsPath(scope)
+	}
+	return path
+}
+
+func confirmRemoval(name, componentType, location string) bool {
 	fmt.Printf("\nThis will remove the %s '%s' from your Claude Code configuration.\n", componentType, name)
+	if location != "" {
+		fmt.Printf("Location: %s\n", location)
+	}
 	fmt.Print("Are you sure you want to continue? (y/N): ")
 
 	reader := bufio.NewReader(os.Stdin)
@@ -92,27 +237,54 @@ func confirmRemoval(name, componentType string) bool {
 	return response == "y" || response == "yes"
 }
 
-func removeAgent(name string) error {
-	// Use project-local agents directory
-	wd, err := os.Getwd()
+func removeAgent(name, scope string) error {
+	// Locate the agent in the scope, including disabled copies
+	agentFile, err := config.FindAgentFileInScope(name, scope)
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
+		return err
 	}
-	agentPath := filepath.Join(wd, ".claude", "agents", name+".md")
-
-	// Check if file exists
-	if _, err := os.Stat(agentPath); os.IsNotExist(err) {
-		return fmt.Errorf("agent file not found: %s", agentPath)
+	if agentFile == nil {
+		return fmt.Errorf("agent file not found for '%s' in the %s scope", name, scope)
 	}
 
 	// Remove the file
-	if err := os.Remove(agentPath); err != nil {
+	if err := os.Remove(agentFile.Path); err != nil {
 		return fmt.Errorf("failed to remove agent file: %w", err)
 	}
 
 	return nil
 }
 
+func removeCommand(name, scope string) error {
+	commandFile, err := config.FindCommandFileInScope(name, scope)
+	if err != nil {
+		return err
+	}
+	if commandFile == nil {
+		return fmt.Errorf("command file not found for '%s' in the %s scope", name, scope)
+	}
+
+	if err := os.Remove(commandFile.Path); err != nil {
+		return fmt.Errorf("failed to remove command file: %w", err)
+	}
+
+	// Drop namespace directories the command leaves empty
+	commandsDir, err := config.GetProjectCommandsPath()
+	if commandFile.Scope == config.ScopeUser {
+		commandsDir, err = config.GetUserCommandsPath()
+	}
+	if err != nil {
+		return nil
+	}
+	for dir := filepath.Dir(commandFile.Path); dir != commandsDir && strings.HasPrefix(dir, commandsDir); dir = filepath.Dir(dir) {
+		if empty, err := isDirEmpty(dir); err != nil || !empty || os.Remove(dir) != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
 func removeHook(name string) error {
 	// Remove hook from Claude settings first
 	if err := config.RemoveHookFromSettings(name); err != nil {
@@ -127,7 +299,7 @@ func removeHook(name string) error {
 
 	// Remove hook script files
 	hooksDir := filepath.Join(wd, ".claude", "hooks")
-	scriptExtensions := []string{".py", ".sh", ".js", ".ts"}
+	scriptExtensions := []string{".py", ".sh", ".js", ".ts", ".go"}
 	for _, ext := range scriptExtensions {
 		scriptPath := filepath.Join(hooksDir, name+ext)
 		if _, err := os.Stat(scriptPath); err == nil {
@@ -139,22 +311,12 @@ func removeHook(name string) error {
 		}
 	}
 
-	// Remove hook-specific config files
+	// Remove conventional hook config files
 	configDir := filepath.Join(wd, ".claude", "config")
 	configFiles := []string{
 		name + ".json",
 		name + "-config.json",
 	}
-	
-	// Special handling for known hooks
-	switch name {
-	case "audio-notification":
-		configFiles = append(configFiles, "audio-notification.json")
-	case "task-notification":
-		configFiles = append(configFiles, "notification.json")
-	case "text-expander":
-		configFiles = append(configFiles, "text-expander.json")
-	}
 
 	for _, configFile := range configFiles {
 		configPath := filepath.Join(configDir, configFile)
@@ -167,50 +329,45 @@ func removeHook(name string) error {
 		}
 	}
 
-	// Remove sound files for audio-related hooks
-	if name == "audio-notification" || name == "task-notification" {
-		soundsDir := filepath.Join(wd, ".claude", "sounds")
-		if _, err := os.Stat(soundsDir); err == nil {
-			// Ask user if they want to remove sound files
-			fmt.Print("Do you want to remove audio files? (y/N): ")
-			reader := bufio.NewReader(os.Stdin)
-			response, err := reader.ReadString('\n')
-			if err == nil {
-				response = strings.ToLower(strings.TrimSpace(response))
-				if response == "y" || response == "yes" {
-					if err := os.RemoveAll(soundsDir); err != nil {
-						fmt.Printf("Warning: failed to remove sounds directory: %v\n", err)
-					} else {
-						fmt.Printf("Removed sounds directory: %s\n", soundsDir)
-					}
-				}
-			}
+	// Remove the files, config and state the hook declares
+	if hook := loadInstalledHookDefinition(name); hook != nil {
+		reader := bufio.NewReader(os.Stdin)
+		for _, cleanup := range hookRemovalPaths(hook) {
+			removeHookPath(wd, cleanup, reader)
 		}
 	}
 
-	// Remove any temporary or state files
-	tempFiles := []string{
-		".claude/last-notification-time",
-		".claude/last-audio-notification",
-		".claude/hook-error.log",
-		".claude/notification-error.log",
+	// Clean up empty directories
+	cleanupEmptyDirectories(wd, name)
+
+	return nil
+}
+
+// removeHookPath removes a declared file or directory, asking first if the entry says to
+func removeHookPath(wd string, cleanup types.HookCleanup, reader *bufio.Reader) {
+	path := filepath.Join(wd, filepath.FromSlash(cleanup.Path))
+	if _, err := os.Stat(path); err != nil {
+		return
 	}
 
-	for _, tempFile := range tempFiles {
-		tempPath := filepath.Join(wd, tempFile)
-		if _, err := os.Stat(tempPath); err == nil {
-			if err := os.Remove(tempPath); err != nil {
-				fmt.Printf("Warning: failed to remove temp file %s: %v\n", tempPath, err)
-			} else {
-				fmt.Printf("Removed temp file: %s\n", tempPath)
-			}
+	if cleanup.Confirm != "" {
+		fmt.Printf("%s (y/N): ", cleanup.Confirm)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Println()
+			return
+		}
+		response = strings.ToLower(strings.TrimSpace(response))
+		if response != "y" && response != "yes" {
+			return
 		}
 	}
 
-	// Clean up empty directories
-	cleanupEmptyDirectories(wd, name)
-
-	return nil
+	if err := os.RemoveAll(path); err != nil {
+		fmt.Printf("Warning: failed to remove %s: %v\n", path, err)
+	} else {
+		fmt.Printf("Removed: %s\n", path)
+	}
 }
 
 func cleanupEmptyDirectories(wd, hookName string) {
@@ -8,6 +8,9 @@ import (
 	"github.com/spf13/viper"
 )
 
+// Version is the cchp version, checked against min_cchp_version in template manifests
+const Version = "0.1.0"
+
 var (
 	cfgFile string
 	verbose bool
@@ -21,7 +24,7 @@ Claude Code hooks and sub-agents efficiently.
 
 You can install pre-built components, create custom ones,
 and manage your Claude Code configuration with ease.`,
-	Version: "0.1.0",
+	Version: Version,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -0,0 +1,417 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/internal/manifest"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+var sourceCmd = &cobra.Command{
+	Use:   "source",
+	Short: "Manage template sources",
+	Long: `Manage additional template sources such as a team's hook and agent collection.
+
+A source is either a local directory used in place or a git repository
+(file://, ssh or https URL) cloned into the cache. Sources contain agents/,
+hooks/ and fragments/ directories, either at the root or under templates/.
+Templates from a source can be referenced explicitly as <source>/<name>.`,
+}
+
+var sourceAddCmd = &cobra.Command{
+	Use:   "add <name> <path-or-url>",
+	Short: "Add a template source",
+	Long: `Add a local directory or git repository as a template source.
+
+Examples:
+  cchp source add team git@github.com:acme/claude-templates.git
+  cchp source add team https://github.com/acme/claude-templates.git --ref v1.2.0
+  cchp source add local ~/claude-templates --priority 10`,
+	Args: cobra.ExactArgs(2),
+	RunE: addTemplateSource,
+}
+
+var sourceRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a template source",
+	Args:  cobra.ExactArgs(1),
+	RunE:  removeTemplateSource,
+}
+
+var sourceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List template sources in priority order",
+	RunE:  listTemplateSources,
+}
+
+var sourceTrustCmd = &cobra.Command{
+	Use:   "trust <source> <public-key>",
+	Short: "Require a source's manifests to be signed by a key",
+	Long: `Add a trusted ed25519 public key to a template source.
+
+Once a source has trusted keys, every component installed from it must come
+with a signed manifest: <name>.manifest.yaml or index.yaml next to a .sig file
+holding the base64 ed25519 signature of the manifest. The manifest's checksums
+then vouch for every template and script file before anything is copied or run.
+
+Keys are base64 encoded 32-byte ed25519 public keys, optionally prefixed with
+"ed25519:". A manifest can be signed with OpenSSL 3, for example:
+
+  openssl pkeyutl -sign -inkey key.pem -rawin -in index.yaml | base64 > index.yaml.sig`,
+	Args: cobra.ExactArgs(2),
+	RunE: trustSourceKey,
+}
+
+var sourceUntrustCmd = &cobra.Command{
+	Use:   "untrust <source> <public-key>",
+	Short: "Remove a trusted key from a source",
+	Args:  cobra.ExactArgs(2),
+	RunE:  untrustSourceKey,
+}
+
+var sourceUpdateCmd = &cobra.Command{
+	Use:   "update [name...]",
+	Short: "Fetch the latest templates for git sources",
+	RunE:  updateTemplateSources,
+}
+
+func init() {
+	rootCmd.AddCommand(sourceCmd)
+	sourceCmd.AddCommand(sourceAddCmd)
+	sourceCmd.AddCommand(sourceRemoveCmd)
+	sourceCmd.AddCommand(sourceListCmd)
+	sourceCmd.AddCommand(sourceUpdateCmd)
+	sourceCmd.AddCommand(sourceTrustCmd)
+	sourceCmd.AddCommand(sourceUntrustCmd)
+
+	sourceAddCmd.Flags().String("ref", "", "Branch or tag to check out (git sources only)")
+	sourceAddCmd.Flags().Int("priority", config.DefaultSourcePriority, "Lookup priority; lower values are searched first")
+}
+
+func addTemplateSource(cmd *cobra.Command, args []string) error {
+	name, location := args[0], args[1]
+	ref, _ := cmd.Flags().GetString("ref")
+	priority, _ := cmd.Flags().GetInt("priority")
+
+	if !isValidComponentName(name) {
+		return fmt.Errorf("invalid source name '%s'. Use lowercase letters, numbers, and hyphens only", name)
+	}
+	if name == assets.OriginProject || name == assets.OriginUser || name == assets.OriginBuiltin {
+		return fmt.Errorf("source name '%s' is reserved", name)
+	}
+
+	sources, err := config.LoadTemplateSources()
+	if err != nil {
+		return err
+	}
+	for _, source := range sources {
+		if source.Name == name {
+			return fmt.Errorf("source '%s' already exists. Remove it first to change its location", name)
+		}
+	}
+
+	source := types.TemplateSource{
+		Name:     name,
+		Location: location,
+		Ref:      ref,
+		Priority: priority,
+	}
+
+	if isGitURL(location) {
+		source.Type = types.SourceTypeGit
+		if err := cloneTemplateSource(source); err != nil {
+			return err
+		}
+	} else {
+		absPath, err := filepath.Abs(expandHome(location))
+		if err != nil {
+			return fmt.Errorf("failed to resolve path: %w", err)
+		}
+		if info, err := os.Stat(absPath); err != nil || !info.IsDir() {
+			return fmt.Errorf("source directory not found: %s", absPath)
+		}
+		if ref != "" {
+			return fmt.Errorf("--ref is only supported for git sources")
+		}
+		source.Type = types.SourceTypeDir
+		source.Location = absPath
+	}
+
+	if err := config.SaveTemplateSources(append(sources, source)); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Added %s source '%s' (%s)\n", source.Type, name, source.Location)
+	fmt.Printf("Install its templates with: cchp install %s/<name>\n", name)
+	return nil
+}
+
+func removeTemplateSource(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	sources, err := config.LoadTemplateSources()
+	if err != nil {
+		return err
+	}
+
+	var remaining []types.TemplateSource
+	var removed *types.TemplateSource
+	for i, source := range sources {
+		if source.Name == name {
+			removed = &sources[i]
+			continue
+		}
+		remaining = append(remaining, source)
+	}
+	if removed == nil {
+		return fmt.Errorf("source '%s' not found", name)
+	}
+
+	if err := config.SaveTemplateSources(remaining); err != nil {
+		return err
+	}
+
+	// Only cached clones are deleted; local directories belong to the user
+	if removed.Type == types.SourceTypeGit {
+		if cloneDir, err := config.GetSourceDir(*removed); err == nil {
+			if err := os.RemoveAll(cloneDir); err != nil {
+				fmt.Printf("Warning: failed to remove cached clone %s: %v\n", cloneDir, err)
+			}
+		}
+	}
+
+	fmt.Printf("✓ Removed source '%s'\n", name)
+	return nil
+}
+
+func listTemplateSources(cmd *cobra.Command, args []string) error {
+	sources, err := config.LoadTemplateSources()
+	if err != nil {
+		return err
+	}
+
+	if len(sources) == 0 {
+		fmt.Println("No template sources configured.")
+		fmt.Println("Use 'cchp source add <name> <path-or-url>' to add one.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PRIORITY\tNAME\tTYPE\tLOCATION\tREF\tTRUSTED KEYS")
+	fmt.Fprintln(w, "--------\t----\t----\t--------\t---\t------------")
+	for _, source := range sources {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\n",
+			source.Priority, source.Name, source.Type, source.Location, source.Ref, len(source.TrustedKeys))
+	}
+	w.Flush()
+	return nil
+}
+
+func updateTemplateSources(cmd *cobra.Command, args []string) error {
+	sources, err := config.LoadTemplateSources()
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range args {
+		wanted[name] = true
+	}
+
+	updated := 0
+	for _, source := range sources {
+		if len(wanted) > 0 && !wanted[source.Name] {
+			continue
+		}
+		delete(wanted, source.Name)
+		if source.Type != types.SourceTypeGit {
+			continue
+		}
+		if err := pullTemplateSource(source); err != nil {
+			return err
+		}
+		updated++
+	}
+
+	for name := range wanted {
+		return fmt.Errorf("source '%s' not found", name)
+	}
+
+	fmt.Printf("✓ Updated %d git source(s)\n", updated)
+	return nil
+}
+
+func trustSourceKey(cmd *cobra.Command, args []string) error {
+	name, key := args[0], strings.TrimSpace(args[1])
+
+	if _, err := manifest.ParsePublicKey(key); err != nil {
+		return err
+	}
+
+	sources, err := config.LoadTemplateSources()
+	if err != nil {
+		return err
+	}
+
+	for i := range sources {
+		if sources[i].Name != name {
+			continue
+		}
+		for _, trusted := range sources[i].TrustedKeys {
+			if sameKey(trusted, key) {
+				fmt.Printf("Key is already trusted for source '%s'\n", name)
+				return nil
+			}
+		}
+		sources[i].TrustedKeys = append(sources[i].TrustedKeys, key)
+		if err := config.SaveTemplateSources(sources); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Trusted key added to source '%s'; its manifests must now be signed\n", name)
+		return nil
+	}
+
+	return fmt.Errorf("source '%s' not found", name)
+}
+
+func untrustSourceKey(cmd *cobra.Command, args []string) error {
+	name, key := args[0], strings.TrimSpace(args[1])
+
+	sources, err := config.LoadTemplateSources()
+	if err != nil {
+		return err
+	}
+
+	for i := range sources {
+		if sources[i].Name != name {
+			continue
+		}
+		var remaining []string
+		for _, trusted := range sources[i].TrustedKeys {
+			if !sameKey(trusted, key) {
+				remaining = append(remaining, trusted)
+			}
+		}
+		if len(remaining) == len(sources[i].TrustedKeys) {
+			return fmt.Errorf("key is not trusted for source '%s'", name)
+		}
+		sources[i].TrustedKeys = remaining
+		if err := config.SaveTemplateSources(sources); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Removed trusted key from source '%s'\n", name)
+		if len(remaining) == 0 {
+			fmt.Println("⚠️  The source has no trusted keys left; signatures are no longer required")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("source '%s' not found", name)
+}
+
+// sameKey compares public keys with or without the "ed25519:" prefix
+func sameKey(a, b string) bool {
+	return strings.TrimPrefix(a, manifest.KeyPrefix) == strings.TrimPrefix(b, manifest.KeyPrefix)
+}
+
+// isGitURL reports whether a source location should be cloned with git
+func isGitURL(location string) bool {
+	for _, prefix := range []string{"file://", "https://", "http://", "ssh://", "git://", "git@"} {
+		if strings.HasPrefix(location, prefix) {
+			return true
+		}
+	}
+	return strings.HasSuffix(location, ".git") && !isExistingDir(location)
+}
+
+func isExistingDir(path string) bool {
+	info, err := os.Stat(expandHome(path))
+	return err == nil && info.IsDir()
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
+
+func cloneTemplateSource(source types.TemplateSource) error {
+	cloneDir, err := config.GetSourceDir(source)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(cloneDir); err != nil {
+		return fmt.Errorf("failed to clear cache directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(cloneDir), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	gitArgs := []string{"clone", "--depth", "1"}
+	if source.Ref != "" {
+		gitArgs = append(gitArgs, "--branch", source.Ref)
+	}
+	gitArgs = append(gitArgs, source.Location, cloneDir)
+
+	fmt.Printf("Cloning %s...\n", source.Location)
+	if err := runGit("", gitArgs...); err != nil {
+		os.RemoveAll(cloneDir)
+		return fmt.Errorf("failed to clone source '%s': %w", source.Name, err)
+	}
+	return nil
+}
+
+func pullTemplateSource(source types.TemplateSource) error {
+	cloneDir, err := config.GetSourceDir(source)
+	if err != nil {
+		return err
+	}
+
+	// Re-clone if the cache was cleared
+	if _, err := os.Stat(filepath.Join(cloneDir, ".git")); err != nil {
+		return cloneTemplateSource(source)
+	}
+
+	ref := source.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	// Shallow clones can't fast-forward, so fetch the ref and move the cache to it.
+	// The clone is owned by cchp, so there are no local changes to keep.
+	fmt.Printf("Updating %s...\n", source.Name)
+	if err := runGit(cloneDir, "fetch", "--depth", "1", "origin", ref); err != nil {
+		return fmt.Errorf("failed to update source '%s': %w", source.Name, err)
+	}
+	if err := runGit(cloneDir, "reset", "--hard", "--quiet", "FETCH_HEAD"); err != nil {
+		return fmt.Errorf("failed to update source '%s': %w", source.Name, err)
+	}
+	if err := runGit(cloneDir, "clean", "-fdq"); err != nil {
+		return fmt.Errorf("failed to update source '%s': %w", source.Name, err)
+	}
+	return nil
+}
+
+func runGit(dir string, args ...string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git not found. Please install git or add it to PATH")
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/internal/manifest"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Make the project match .claude/cchp.lock",
+	Long: `Install, repair and remove components so the project's .claude directory
+matches the lockfile written by 'cchp install'.
+
+Commit .claude/cchp.lock to share a setup; teammates run 'cchp sync' to get
+the same agents and hooks with the same template variables. Components installed
+with user scope are recorded in ~/.config/claude-helper/cchp.lock instead and
+are never synced.
+
+  - Components in the lockfile that are missing are installed
+  - Installed files whose checksum differs from the lockfile are reinstalled
+  - Project components installed from a template but not in the lockfile are removed
+
+Components are only reinstalled from the exact template content recorded in the
+lockfile. If a template has changed since, sync stops; check out the locked
+revision of its source, or run 'cchp upgrade' to move the lockfile forward.`,
+	RunE: syncComponents,
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().Bool("dry-run", false, "Show what would change without changing anything")
+	syncCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
+}
+
+// syncAction is a single change needed to bring the project in line with the lockfile
+type syncAction struct {
+	Kind      string // "install", "repair" or "remove"
+	Name      string
+	Type      string
+	Component types.LockedComponent
+	Reason    string
+}
+
+func syncComponents(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	lockPath, err := config.GetLockfilePath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(lockPath); os.IsNotExist(err) {
+		return fmt.Errorf("no lockfile found at %s. Install components with 'cchp install' first", lockPath)
+	}
+
+	lock, err := config.LoadLockfile()
+	if err != nil {
+		return err
+	}
+
+	actions, err := planSync(lock)
+	if err != nil {
+		return err
+	}
+
+	if len(actions) == 0 {
+		fmt.Println("✓ Project is in sync with the lockfile")
+		return nil
+	}
+
+	fmt.Println("Changes needed to match the lockfile:")
+	for _, action := range actions {
+		fmt.Printf("  %-8s %s %s (%s)\n", action.Kind, action.Type, action.Name, action.Reason)
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if !skipConfirm && !confirmSync(len(actions)) {
+		fmt.Println("Sync cancelled.")
+		return nil
+	}
+
+	failed := 0
+	for _, action := range actions {
+		fmt.Println()
+		if err := applySyncAction(action); err != nil {
+			fmt.Printf("❌ Failed to %s %s '%s': %v\n", action.Kind, action.Type, action.Name, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d change(s) failed", failed, len(actions))
+	}
+
+	fmt.Printf("\n✓ Applied %d change(s); project matches the lockfile\n", len(actions))
+	return nil
+}
+
+// planSync compares the lockfile against the project and returns the changes to make
+func planSync(lock *types.Lockfile) ([]syncAction, error) {
+	var actions []syncAction
+	var changedTemplates []string
+
+	for _, key := range sortedLockedKeys(lock) {
+		component := lock.Components[key]
+		_, name, _ := types.SplitLockKey(key)
+		// Components from the user lockfile are personal, not part of the project
+		if component.Scope == config.ScopeUser {
+			continue
+		}
+		if component.TemplateHash == "" {
+			// Lockfiles written before template hashes were recorded
+			warnLockedVersion(name, component)
+		}
+
+		if disabled, _ := config.IsAgentDisabled(name); component.Type == "agent" && disabled {
+			fmt.Printf("⚠️  Agent '%s' is disabled; leaving it as is\n", name)
+			continue
+		}
+		if commandFile, _ := config.FindCommandFile(name); component.Type == "command" && commandFile != nil && commandFile.Disabled {
+			fmt.Printf("⚠️  Command '%s' is disabled; leaving it as is\n", name)
+			continue
+		}
+
+		installed, err := isComponentInstalled(name, component.Type, component.Scope)
+		if err != nil {
+			return nil, err
+		}
+		action := syncAction{Kind: "install", Name: name, Type: component.Type, Component: component, Reason: "missing"}
+		if installed {
+			changed := changedLockedFiles(component)
+			if len(changed) == 0 {
+				continue
+			}
+			action.Kind, action.Reason = "repair", strings.Join(changed, ", ")
+		}
+
+		// Reinstalling from a template that moved on would not reproduce the locked files
+		if changed, err := lockedTemplateChanged(component); err != nil {
+			return nil, err
+		} else if changed {
+			changedTemplates = append(changedTemplates, name)
+			continue
+		}
+		actions = append(actions, action)
+	}
+
+	if len(changedTemplates) > 0 {
+		return nil, fmt.Errorf("the templates of %s changed since the lockfile was written. Check out the locked revision of their source, or run 'cchp upgrade' to update the lockfile", strings.Join(changedTemplates, ", "))
+	}
+
+	extras, err := findUnlockedComponents(lock)
+	if err != nil {
+		return nil, err
+	}
+	actions = append(actions, extras...)
+
+	return actions, nil
+}
+
+// changedLockedFiles returns the locked files that are missing or differ from their recorded checksum
+func changedLockedFiles(component types.LockedComponent) []string {
+	files := make([]string, 0, len(component.Files))
+	for file := range component.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var changed []string
+	for _, file := range files {
+		path, err := config.ResolveLockedFile(file)
+		if err != nil {
+			continue
+		}
+		hash, err := config.HashFile(path)
+		switch {
+		case os.IsNotExist(err):
+			changed = append(changed, file+" missing")
+		case err != nil || hash != component.Files[file]:
+			changed = append(changed, file+" modified")
+		}
+	}
+	return changed
+}
+
+// findUnlockedComponents returns project components installed from a known template
+// that the lockfile doesn't list
+func findUnlockedComponents(lock *types.Lockfile) ([]syncAction, error) {
+	var actions []syncAction
+
+	agents, err := assets.ListTemplates("agent")
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range agents {
+		if _, locked := lock.Components[types.LockKey("agent", template.Name, "")]; locked {
+			continue
+		}
+		// Agents in ~/.claude/agents are personal and not managed by the project lockfile
+		if agentFile, err := config.FindAgentFile(template.Name); err == nil && agentFile != nil && agentFile.Scope == config.ScopeProject {
+			actions = append(actions, syncAction{Kind: "remove", Name: template.Name, Type: "agent", Reason: "not in lockfile"})
+		}
+	}
+
+	hooks, err := assets.ListTemplates("hook")
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range hooks {
+		if _, locked := lock.Components[types.LockKey("hook", template.Name, "")]; locked {
+			continue
+		}
+		if installed, err := config.IsHookInstalled(template.Name); err == nil && installed {
+			actions = append(actions, syncAction{Kind: "remove", Name: template.Name, Type: "hook", Reason: "not in lockfile"})
+		}
+	}
+
+	commands, err := assets.ListTemplates("command")
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range commands {
+		if _, locked := lock.Components[types.LockKey("command", template.Name, "")]; locked {
+			continue
+		}
+		// Like agents, commands in ~/.claude/commands are personal
+		if commandFile, err := config.FindCommandFile(template.Name); err == nil && commandFile != nil && commandFile.Scope == config.ScopeProject {
+			actions = append(actions, syncAction{Kind: "remove", Name: template.Name, Type: "command", Reason: "not in lockfile"})
+		}
+	}
+
+	mcpTemplates, err := assets.ListTemplates("mcp")
+	if err != nil {
+		return nil, err
+	}
+	projectServers, err := config.LoadMCPServers(config.ScopeProject)
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range mcpTemplates {
+		if _, locked := lock.Components[types.LockKey("mcp", template.Name, "")]; locked {
+			continue
+		}
+		// Only .mcp.json is shared with the project; ~/.claude.json is personal
+		if _, ok := projectServers[template.Name]; ok {
+			actions = append(actions, syncAction{Kind: "remove", Name: template.Name, Type: "mcp", Reason: "not in lockfile"})
+		}
+	}
+
+	presets, err := assets.ListTemplates("permission")
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range presets {
+		if _, locked := lock.Components[types.LockKey("permission", template.Name, "")]; locked {
+			continue
+		}
+		// Only .claude/settings.json is shared; user and local settings are personal
+		if installed, err := isPermissionPresetInstalled(template.Name, config.ScopeProject); err == nil && installed {
+			actions = append(actions, syncAction{Kind: "remove", Name: template.Name, Type: "permission", Reason: "not in lockfile"})
+		}
+	}
+
+	return actions, nil
+}
+
+// lockedTemplateChanged reports whether the template a component was installed
+// from no longer has the content recorded in the lockfile
+func lockedTemplateChanged(component types.LockedComponent) (bool, error) {
+	if component.TemplateHash == "" {
+		return false, nil
+	}
+	template, err := assets.FindTemplate(component.Type, component.Template)
+	if err != nil {
+		return false, fmt.Errorf("failed to find template '%s': %w", component.Template, err)
+	}
+	hash, err := manifest.ContentHash(template)
+	if err != nil {
+		return false, err
+	}
+	return hash != component.TemplateHash, nil
+}
+
+// warnLockedVersion warns when the template has moved on from the locked version
+func warnLockedVersion(name string, component types.LockedComponent) {
+	if component.Version == "" {
+		return
+	}
+	template, err := assets.FindTemplate(component.Type, component.Template)
+	if err != nil {
+		return
+	}
+	componentManifest, err := manifest.Load(template)
+	if err != nil || componentManifest == nil || componentManifest.Version == "" {
+		return
+	}
+	if componentManifest.Version != component.Version {
+		fmt.Printf("⚠️  %s '%s' is locked at version %s but the template is now %s\n",
+			component.Type, name, component.Version, componentManifest.Version)
+	}
+}
+
+func applySyncAction(action syncAction) error {
+	switch action.Kind {
+	case "install", "repair":
+		// Agents get their template variables back and hooks the answers to their questions
+		return installComponentFromTemplate(action.Component.Template, installOptions{
+			Force:    true,
+			Defaults: action.Component.Params,
+			Scope:    action.Component.Scope,
+		})
+	case "remove":
+		if err := removeInstalledComponent(action.Name, action.Type, action.Component.Scope); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Removed %s '%s'\n", action.Type, action.Name)
+		return nil
+	default:
+		return fmt.Errorf("unknown sync action: %s", action.Kind)
+	}
+}
+
+func confirmSync(count int) bool {
+	fmt.Printf("\nThis will apply %d change(s) to your Claude Code configuration.\n", count)
+	fmt.Print("Are you sure you want to continue? (y/N): ")
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Work with component templates",
+	Long:  `Commands for maintaining agent and hook templates.`,
+}
+
+var templateMigrateCmd = &cobra.Command{
+	Use:   "migrate [path...]",
+	Short: "Convert legacy JSON hook templates to the canonical schema",
+	Long: `Convert hook templates in the legacy JSON format, whose command named an
+inline "script", into the canonical hook schema used by the YAML templates.
+
+The inline script is written next to the template as <name>.sh, which is
+installed into .claude/hooks with the hook, and the command is changed to run
+it. The converted template replaces the legacy file.
+
+Paths can be template files or template directories. Without paths the
+project (.claude/templates) and user (~/.config/claude-helper/templates)
+template directories are migrated.
+
+Examples:
+  cchp template migrate
+  cchp template migrate ~/team-templates --dry-run
+  cchp template migrate hooks/format-code.json --format json`,
+	RunE: migrateTemplates,
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateMigrateCmd)
+	templateMigrateCmd.Flags().String("format", types.HookFormatYAML, "Encoding of migrated templates (yaml or json)")
+	templateMigrateCmd.Flags().Bool("dry-run", false, "Show what would be migrated without changing anything")
+	templateMigrateCmd.Flags().BoolP("force", "f", false, "Overwrite existing templates and scripts")
+}
+
+func migrateTemplates(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if format != types.HookFormatYAML && format != types.HookFormatJSON {
+		return fmt.Errorf("invalid format '%s' (use yaml or json)", format)
+	}
+
+	paths := args
+	if len(paths) == 0 {
+		for _, dir := range []func() (string, error){assets.GetProjectTemplatesDir, assets.GetUserTemplatesDir} {
+			if path, err := dir(); err == nil {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	files, err := findLegacyHookCandidates(paths, len(args) > 0)
+	if err != nil {
+		return err
+	}
+
+	migrated, failed := 0, 0
+	for _, file := range files {
+		changed, err := migrateHookTemplate(file, format, dryRun, force)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", file, err)
+			failed++
+			continue
+		}
+		if changed {
+			migrated++
+		}
+	}
+
+	if migrated == 0 && failed == 0 {
+		fmt.Println("No legacy hook templates found.")
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("\n%d template(s) would be migrated\n", migrated)
+	} else {
+		fmt.Printf("\n✓ Migrated %d template(s)\n", migrated)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to migrate %d template(s)", failed)
+	}
+	return nil
+}
+
+// findLegacyHookCandidates returns the JSON hook templates under paths. Directories are
+// searched in their hooks/ subdirectory when they have one. Missing paths are only an
+// error when they were given explicitly.
+func findLegacyHookCandidates(paths []string, explicit bool) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if explicit {
+				return nil, fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			continue
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		dir := path
+		if hooksDir := filepath.Join(path, "hooks"); isDirectory(hooksDir) {
+			dir = hooksDir
+		}
+		matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// migrateHookTemplate converts one legacy hook template. It reports false for
+// templates that already use the canonical schema.
+func migrateHookTemplate(path, format string, dryRun, force bool) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	legacy, err := types.ParseLegacyHook(content, types.HookFormatForPath(path))
+	if err != nil {
+		return false, err
+	}
+	if legacy.Script == "" {
+		return false, nil
+	}
+
+	dir := filepath.Dir(path)
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	scriptPath := filepath.Join(dir, name+".sh")
+	targetPath := filepath.Join(dir, name+"."+format)
+
+	hook := legacy.Hook
+	if hook.Name == "" {
+		hook.Name = name
+	}
+	hook.Command = "bash .claude/hooks/" + name + ".sh"
+
+	if !force {
+		for _, existing := range []string{scriptPath, targetPath} {
+			if existing == path {
+				continue
+			}
+			if _, err := os.Stat(existing); err == nil {
+				return false, fmt.Errorf("%s already exists. Use --force to overwrite it", existing)
+			}
+		}
+	}
+
+	encoded, err := types.EncodeHook(&hook, format)
+	if err != nil {
+		return false, err
+	}
+
+	if dryRun {
+		fmt.Printf("Would migrate %s -> %s (script: %s)\n", path, targetPath, scriptPath)
+		return true, nil
+	}
+
+	script := legacy.Script
+	if !strings.HasSuffix(script, "\n") {
+		script += "\n"
+	}
+	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		return false, fmt.Errorf("failed to write script: %w", err)
+	}
+	if err := os.WriteFile(targetPath, encoded, 0644); err != nil {
+		return false, fmt.Errorf("failed to write template: %w", err)
+	}
+	if targetPath != path {
+		if err := os.Remove(path); err != nil {
+			return false, fmt.Errorf("failed to remove legacy template: %w", err)
+		}
+	}
+
+	fmt.Printf("✓ Migrated %s -> %s\n", path, targetPath)
+	fmt.Printf("  Script written to %s\n", scriptPath)
+	if strings.Contains(legacy.Script, "$1") {
+		fmt.Printf("⚠️  The script reads $1; hooks receive their input as JSON on stdin, so review it before installing\n")
+	}
+	return true, nil
+}
@@ -0,0 +1,602 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/internal/manifest"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Show installed components with newer templates",
+	Long: `Compare the components recorded in .claude/cchp.lock with their templates
+and report newer versions, template changes and local edits to installed files.`,
+	RunE: listOutdatedComponents,
+}
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [name...]",
+	Short: "Upgrade installed components to their latest templates",
+	Long: `Upgrade installed components to the latest version of their templates.
+
+Without arguments every outdated component in .claude/cchp.lock is upgraded.
+The changelog and a diff of each file are shown before anything changes.
+
+Files you edited after installing are merged three ways with the new template,
+using the pristine copy saved at install time as the common ancestor. When the
+merge conflicts the component is left untouched; use --force to replace your
+edits with the new template instead.
+
+When components of different types share a name, give the one to upgrade as
+<type>/<name>, e.g. agent/reviewer.`,
+	RunE: upgradeComponents,
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	rootCmd.AddCommand(upgradeCmd)
+
+	upgradeCmd.Flags().Bool("dry-run", false, "Show changelogs and diffs without changing anything")
+	upgradeCmd.Flags().BoolP("force", "f", false, "Overwrite local edits instead of merging them")
+	upgradeCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
+}
+
+// componentUpdate compares an installed component with its current template
+type componentUpdate struct {
+	Name     string
+	Locked   types.LockedComponent
+	Manifest *types.ComponentManifest
+	Latest   string            // Version of the current template, if it has a manifest
+	Files    map[string][]byte // Files the current template would install
+	Newer    bool              // The template has a newer version
+	Changed  bool              // The template renders differently from what was installed
+	Modified []string          // Installed files edited since install
+	Removed  []string          // Installed files the current template no longer has
+}
+
+// Outdated reports whether upgrading would change the component
+func (u *componentUpdate) Outdated() bool {
+	return u.Newer || u.Changed
+}
+
+// Status describes the component for 'cchp outdated'
+func (u *componentUpdate) Status() string {
+	status := "up to date"
+	switch {
+	case u.Newer:
+		status = "update available"
+	case u.Changed:
+		status = "template changed"
+	}
+	if len(u.Modified) > 0 {
+		status += ", modified locally"
+	}
+	return status
+}
+
+func listOutdatedComponents(cmd *cobra.Command, args []string) error {
+	lock, err := config.LoadLockfile()
+	if err != nil {
+		return err
+	}
+
+	if len(lock.Components) == 0 {
+		fmt.Println("No components recorded in .claude/cchp.lock.")
+		fmt.Println("Components installed with 'cchp install' are tracked automatically.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tINSTALLED\tLATEST\tSOURCE\tSTATUS")
+	fmt.Fprintln(w, "----\t----\t---------\t------\t------\t------")
+
+	outdated := 0
+	for _, key := range sortedLockedKeys(lock) {
+		entry := lock.Components[key]
+		_, name, _ := types.SplitLockKey(key)
+		update, err := checkComponentUpdate(name, entry)
+		if err != nil {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				name, entry.Type, versionOrDash(entry.Version), "-", entry.Source, "error: "+err.Error())
+			continue
+		}
+		if update.Outdated() {
+			outdated++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			name, entry.Type, versionOrDash(entry.Version), versionOrDash(update.Latest), entry.Source, update.Status())
+	}
+	w.Flush()
+
+	if outdated > 0 {
+		fmt.Printf("\n%d component(s) can be upgraded with 'cchp upgrade'\n", outdated)
+	}
+	return nil
+}
+
+func upgradeComponents(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	lock, err := config.LoadLockfile()
+	if err != nil {
+		return err
+	}
+
+	keys := args
+	if len(keys) == 0 {
+		keys = sortedLockedKeys(lock)
+	}
+
+	refused := 0
+	upgraded := 0
+	for _, arg := range keys {
+		key, err := lockedKeyFor(lock, arg)
+		if err != nil {
+			return err
+		}
+		entry := lock.Components[key]
+		_, name, _ := types.SplitLockKey(key)
+
+		update, err := checkComponentUpdate(name, entry)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", name, err)
+			refused++
+			continue
+		}
+		if !update.Outdated() {
+			if len(args) > 0 {
+				fmt.Printf("✓ %s '%s' is up to date\n", entry.Type, name)
+			}
+			continue
+		}
+
+		results, problems := planComponentUpgrade(update, force)
+		printComponentUpgrade(update, results)
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				fmt.Printf("❌ %s\n", problem)
+			}
+			fmt.Printf("Refusing to upgrade '%s'. Resolve the conflict by hand or use --force to discard local edits.\n\n", name)
+			refused++
+			continue
+		}
+
+		if dryRun {
+			continue
+		}
+		if !skipConfirm && !confirmUpgrade(name) {
+			fmt.Println("Skipped.")
+			continue
+		}
+
+		if err := applyComponentUpgrade(update, results); err != nil {
+			fmt.Printf("❌ Failed to upgrade '%s': %v\n", name, err)
+			refused++
+			continue
+		}
+		upgraded++
+	}
+
+	if !dryRun && upgraded > 0 {
+		fmt.Printf("\n✓ Upgraded %d component(s)\n", upgraded)
+	}
+	if refused > 0 {
+		return fmt.Errorf("%d component(s) could not be upgraded", refused)
+	}
+	if upgraded == 0 && !dryRun && len(args) == 0 {
+		fmt.Println("✓ All components are up to date")
+	}
+	return nil
+}
+
+// checkComponentUpdate renders a locked component's current template and compares
+// it with the lockfile and the installed files
+func checkComponentUpdate(name string, entry types.LockedComponent) (*componentUpdate, error) {
+	template, err := assets.FindTemplate(entry.Type, entry.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &componentUpdate{Name: name, Locked: entry}
+
+	if componentManifest, err := manifest.Load(template); err == nil && componentManifest != nil {
+		update.Manifest = componentManifest
+		update.Latest = componentManifest.Version
+	}
+	if entry.Version != "" && update.Latest != "" {
+		if cmp, err := manifest.CompareVersions(update.Latest, entry.Version); err == nil {
+			update.Newer = cmp > 0
+		}
+	}
+
+	update.Files, err = renderComponentFiles(name, entry.Scope, template, entry.Params)
+	if err != nil {
+		return nil, err
+	}
+	for file, data := range update.Files {
+		if config.HashBytes(data) != entry.Files[file] {
+			update.Changed = true
+		}
+	}
+
+	// Settings entries, MCP servers and permission rules aren't files; the template hash covers them
+	if entry.TemplateHash != "" {
+		if hash, err := manifest.ContentHash(template); err == nil && hash != entry.TemplateHash {
+			update.Changed = true
+		}
+	}
+
+	for file := range entry.Files {
+		if _, ok := update.Files[file]; !ok {
+			update.Removed = append(update.Removed, file)
+			update.Changed = true
+		}
+	}
+	sort.Strings(update.Removed)
+
+	for file, hash := range entry.Files {
+		path, err := config.ResolveLockedFile(file)
+		if err != nil {
+			return nil, err
+		}
+		// Missing files are simply reinstalled; only edited files need merging
+		if current, err := config.HashFile(path); err == nil && current != hash {
+			update.Modified = append(update.Modified, file)
+		}
+	}
+	sort.Strings(update.Modified)
+
+	return update, nil
+}
+
+// renderComponentFiles returns the files installing a template would write,
+// keyed by their slash-separated path relative to the project root
+func renderComponentFiles(name, scope string, template *assets.Template, params map[string]string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	componentType := template.Type
+
+	switch componentType {
+	case "agent":
+		content, err := template.ReadFile()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		flattened, err := flattenAgentTemplate(name, content, nil)
+		if err != nil {
+			return nil, err
+		}
+		rendered, _, err := renderAgentTemplate(name, flattened, nil, params, false)
+		if err != nil {
+			return nil, err
+		}
+		installed, err := installedComponentFiles(name, template, scope)
+		if err != nil {
+			return nil, err
+		}
+		files[installed[0]] = []byte(rendered)
+
+	case "hook":
+		for _, file := range hookScriptFiles(name) {
+			data, err := template.ReadSibling(path.Base(file))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read hook script: %w", err)
+			}
+			files[file] = data
+		}
+
+		declared, err := trackedHookFiles(template)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range declared {
+			data, err := readHookFile(template, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", file.Dest, err)
+			}
+			files[hookFileDest(file)] = data
+		}
+
+	case "command":
+		content, err := template.ReadFile()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		installed, err := installedComponentFiles(name, template, scope)
+		if err != nil {
+			return nil, err
+		}
+		files[installed[0]] = content
+
+	case "mcp":
+		// MCP servers are entries in .mcp.json rather than files; they upgrade with their version
+
+	case "permission":
+		// Permission presets are rules in settings files; they upgrade with their version
+
+	default:
+		return nil, fmt.Errorf("unsupported component type: %s", componentType)
+	}
+
+	return files, nil
+}
+
+// upgradeResult is the planned new content of one installed file
+type upgradeResult struct {
+	File    string
+	Current []byte // Installed content, nil if the file is missing
+	Content []byte // Content after the upgrade
+	Merged  bool   // Content is a merge of local edits and the new template
+}
+
+// planComponentUpgrade works out the new content of each file, merging local edits
+// with the new template. It returns the files whose edits can't be merged.
+func planComponentUpgrade(update *componentUpdate, force bool) ([]upgradeResult, []string) {
+	modified := make(map[string]bool)
+	for _, file := range update.Modified {
+		modified[file] = true
+	}
+
+	files := make([]string, 0, len(update.Files))
+	for file := range update.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var results []upgradeResult
+	var problems []string
+	for _, file := range files {
+		result := upgradeResult{File: file, Content: update.Files[file]}
+		if path, err := config.ResolveLockedFile(file); err == nil {
+			if current, err := os.ReadFile(path); err == nil {
+				result.Current = current
+			}
+		}
+
+		if modified[file] && !force {
+			base, err := config.LoadBaseFile(file)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s was edited locally and no pristine copy is available to merge with", file))
+				continue
+			}
+			merged, conflicts, err := mergeFileContents(result.Current, base, result.Content)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s was edited locally and could not be merged: %v", file, err))
+				continue
+			}
+			if conflicts > 0 {
+				problems = append(problems, fmt.Sprintf("%s was edited locally and conflicts with the new template (%d conflict(s))", file, conflicts))
+				continue
+			}
+			result.Content = merged
+			result.Merged = true
+		}
+
+		results = append(results, result)
+	}
+
+	return results, problems
+}
+
+func printComponentUpgrade(update *componentUpdate, results []upgradeResult) {
+	from := versionOrDash(update.Locked.Version)
+	to := versionOrDash(update.Latest)
+	fmt.Printf("\n%s '%s': %s → %s\n", update.Locked.Type, update.Name, from, to)
+
+	if update.Newer && update.Manifest != nil && update.Manifest.Changelog != "" {
+		fmt.Println("Changelog:")
+		for _, line := range strings.Split(strings.TrimRight(update.Manifest.Changelog, "\n"), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+
+	for _, result := range results {
+		if result.Merged {
+			fmt.Printf("Merging local edits in %s\n", result.File)
+		}
+		diff, err := diffFileContents(result.File, result.Current, result.Content)
+		if err != nil {
+			fmt.Printf("⚠️  Unable to show diff for %s: %v\n", result.File, err)
+			continue
+		}
+		if diff != "" {
+			fmt.Print(diff)
+		}
+	}
+	for _, file := range update.Removed {
+		fmt.Printf("Removing %s (no longer part of the template)\n", file)
+	}
+}
+
+// applyComponentUpgrade reinstalls the component from its template and writes back merged files
+func applyComponentUpgrade(update *componentUpdate, results []upgradeResult) error {
+
+	// Files the template dropped would otherwise be recorded in the lockfile again
+	for _, file := range update.Removed {
+		path, err := config.ResolveLockedFile(file)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+	}
+
+	err := installComponentFromTemplate(update.Locked.Template, installOptions{
+		Force:     true,
+		Defaults:  update.Locked.Params,
+		SkipSetup: true,
+		Scope:     update.Locked.Scope,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The lockfile now records the new template's files; merged files keep the
+	// user's edits on top of them
+	for _, result := range results {
+		if !result.Merged {
+			continue
+		}
+		path, err := config.ResolveLockedFile(result.File)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", result.File, err)
+		}
+		if err := os.WriteFile(path, result.Content, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write merged %s: %w", result.File, err)
+		}
+		fmt.Printf("✓ Kept local edits in %s\n", result.File)
+	}
+	return nil
+}
+
+// mergeFileContents performs a three-way merge with git merge-file and returns
+// the merged content and the number of conflicts
+func mergeFileContents(current, base, other []byte) ([]byte, int, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, 0, fmt.Errorf("git not found. Please install git or add it to PATH")
+	}
+
+	tempDir, err := os.MkdirTemp("", "cchp-merge-*")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	paths := make([]string, 3)
+	for i, data := range [][]byte{current, base, other} {
+		paths[i] = filepath.Join(tempDir, fmt.Sprintf("%d", i))
+		if err := os.WriteFile(paths[i], data, 0644); err != nil {
+			return nil, 0, fmt.Errorf("failed to write temp file: %w", err)
+		}
+	}
+
+	cmd := exec.Command("git", "merge-file", "-p",
+		"-L", "installed", "-L", "base", "-L", "new",
+		paths[0], paths[1], paths[2])
+	output, err := cmd.Output()
+
+	// git merge-file exits with the number of conflicts, or a negative value on error
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return output, 0, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128:
+		return output, exitErr.ExitCode(), nil
+	default:
+		return nil, 0, fmt.Errorf("git merge-file failed: %w", err)
+	}
+}
+
+// diffFileContents returns a unified diff between the installed and upgraded content
+func diffFileContents(file string, current, updated []byte) (string, error) {
+	if string(current) == string(updated) {
+		return "", nil
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git not found")
+	}
+
+	tempDir, err := os.MkdirTemp("", "cchp-diff-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	oldPath := filepath.Join("installed", filepath.FromSlash(file))
+	newPath := filepath.Join("upgraded", filepath.FromSlash(file))
+	for path, data := range map[string][]byte{oldPath: current, newPath: updated} {
+		fullPath := filepath.Join(tempDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return "", fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		if err := os.WriteFile(fullPath, data, 0644); err != nil {
+			return "", fmt.Errorf("failed to write temp file: %w", err)
+		}
+	}
+
+	cmd := exec.Command("git", "diff", "--no-index", "--no-color", "--no-prefix", oldPath, newPath)
+	cmd.Dir = tempDir
+	output, err := cmd.Output()
+
+	// git diff --no-index exits with 1 when the files differ
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return string(output), nil
+}
+
+func sortedLockedKeys(lock *types.Lockfile) []string {
+	keys := make([]string, 0, len(lock.Components))
+	for key := range lock.Components {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lockedKeyFor returns the lock key a component argument refers to: a key such
+// as agent/reviewer, or a name only one locked component has
+func lockedKeyFor(lock *types.Lockfile, arg string) (string, error) {
+	if _, ok := lock.Components[arg]; ok {
+		return arg, nil
+	}
+
+	var matches []string
+	for _, key := range sortedLockedKeys(lock) {
+		if _, name, _ := types.SplitLockKey(key); name == arg {
+			matches = append(matches, key)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("component '%s' is not recorded in .claude/cchp.lock. Reinstall it with 'cchp install %s --force' to track it", arg, arg)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("'%s' matches %s in .claude/cchp.lock; name one of them", arg, strings.Join(matches, ", "))
+	}
+}
+
+func versionOrDash(version string) string {
+	if version == "" {
+		return "-"
+	}
+	return version
+}
+
+func confirmUpgrade(name string) bool {
+	fmt.Printf("Upgrade '%s'? (y/N): ", name)
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
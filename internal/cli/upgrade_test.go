@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+func TestUpgradeWithRemovedVariable(t *testing.T) {
+	project := useTempProject(t)
+
+	// The new version of the agent dropped its strictness variable
+	dir := filepath.Join(project, ".claude", "templates", "agents")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	agent := `---
+name: reviewer
+description: Reviews code
+variables:
+  - name: language
+    default: Go
+---
+
+Review {{.language}} code.
+`
+	if err := os.WriteFile(filepath.Join(dir, "reviewer.md"), []byte(agent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := types.LockedComponent{
+		Type:     "agent",
+		Template: "reviewer",
+		Source:   "project",
+		Params:   map[string]string{"language": "Rust", "strictness": "high"},
+		Files:    map[string]string{".claude/agents/reviewer.md": "sha256:old"},
+	}
+	if err := config.RecordLockedComponent("reviewer", entry); err != nil {
+		t.Fatal(err)
+	}
+
+	update, err := checkComponentUpdate("reviewer", entry)
+	if err != nil {
+		t.Fatalf("checkComponentUpdate() error = %v", err)
+	}
+	if !update.Changed {
+		t.Errorf("checkComponentUpdate() Changed = false, want true")
+	}
+	if got := string(update.Files[".claude/agents/reviewer.md"]); !strings.Contains(got, "Review Rust code.") {
+		t.Errorf("checkComponentUpdate() rendered %q, want the remembered language", got)
+	}
+
+	if err := applyComponentUpgrade(update, nil); err != nil {
+		t.Fatalf("applyComponentUpgrade() error = %v", err)
+	}
+	installed, err := os.ReadFile(filepath.Join(project, ".claude", "agents", "reviewer.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(installed), "Review Rust code.") {
+		t.Errorf("installed agent = %q, want the remembered language", installed)
+	}
+
+	lock, err := config.LoadLockfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := lock.Components["agent/reviewer"].Params, map[string]string{"language": "Rust"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("locked params = %v, want %v", got, want)
+	}
+}
+
+func TestHookFilesAreTracked(t *testing.T) {
+	project := useTempProject(t)
+	useEmptyStdin(t)
+
+	dir := filepath.Join(project, ".claude", "templates", "hooks")
+	if err := os.MkdirAll(filepath.Join(dir, "lint"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	hook := `name: lint
+description: Lints edited files
+event: PostToolUse
+command: bash .claude/hooks/lint/run.sh
+files:
+  - source: lint/run.sh
+    dest: .claude/hooks/lint/run.sh
+    mode: "0755"
+  - source: lint/rules.txt
+    dest: .claude/lint-rules.txt
+    keep_existing: true
+`
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(filepath.Join(dir, "lint.yaml"), hook)
+	writeFile(filepath.Join(dir, "lint", "run.sh"), "echo v1\n")
+	writeFile(filepath.Join(dir, "lint", "rules.txt"), "rules\n")
+
+	if err := installComponentFromTemplate("lint", installOptions{}); err != nil {
+		t.Fatalf("installComponentFromTemplate() error = %v", err)
+	}
+
+	lock, err := config.LoadLockfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := lock.Components["hook/lint"]
+	if got, want := entry.Files[".claude/hooks/lint/run.sh"], config.HashBytes([]byte("echo v1\n")); got != want {
+		t.Errorf("locked hash of run.sh = %q, want %q", got, want)
+	}
+	if _, ok := entry.Files[".claude/lint-rules.txt"]; ok {
+		t.Errorf("keep_existing file is tracked in the lockfile")
+	}
+
+	// A new template version and a local edit both show up in the upgrade check
+	writeFile(filepath.Join(dir, "lint", "run.sh"), "echo v2\n")
+	writeFile(filepath.Join(project, ".claude", "hooks", "lint", "run.sh"), "echo v1 edited\n")
+
+	update, err := checkComponentUpdate("lint", entry)
+	if err != nil {
+		t.Fatalf("checkComponentUpdate() error = %v", err)
+	}
+	if !update.Changed {
+		t.Errorf("checkComponentUpdate() Changed = false, want true")
+	}
+	if got := string(update.Files[".claude/hooks/lint/run.sh"]); got != "echo v2\n" {
+		t.Errorf("checkComponentUpdate() run.sh = %q, want the new template version", got)
+	}
+	if !reflect.DeepEqual(update.Modified, []string{".claude/hooks/lint/run.sh"}) {
+		t.Errorf("checkComponentUpdate() Modified = %v, want run.sh", update.Modified)
+	}
+}
@@ -64,6 +64,133 @@ func GetSettingsPath() (string, error) {
 	return filepath.Join(claudePath, "settings.json"), nil
 }
 
+// Agent and command scopes, in lookup order
+const (
+	ScopeProject = "project"
+	ScopeUser    = "user"
+)
+
+// GetProjectAgentsPath returns the project-local agents directory (.claude/agents)
+func GetProjectAgentsPath() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return filepath.Join(wd, ".claude", "agents"), nil
+}
+
+// GetUserAgentsPath returns the user-level agents directory (~/.claude/agents)
+func GetUserAgentsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".claude", "agents"), nil
+}
+
+// GetProjectCommandsPath returns the project-local slash commands directory (.claude/commands)
+func GetProjectCommandsPath() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return filepath.Join(wd, ".claude", "commands"), nil
+}
+
+// GetUserCommandsPath returns the user-level slash commands directory (~/.claude/commands)
+func GetUserCommandsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".claude", "commands"), nil
+}
+
+// ComponentFile describes an agent or command file found in one of the scopes
+type ComponentFile struct {
+	Path     string
+	Scope    string
+	Disabled bool // File carries the .md.disabled suffix
+}
+
+// scopeDir pairs a scope with the directory it keeps one kind of component in
+type scopeDir struct {
+	name   string
+	dirFor func() (string, error)
+}
+
+var (
+	agentScopeDirs   = []scopeDir{{ScopeProject, GetProjectAgentsPath}, {ScopeUser, GetUserAgentsPath}}
+	commandScopeDirs = []scopeDir{{ScopeProject, GetProjectCommandsPath}, {ScopeUser, GetUserCommandsPath}}
+)
+
+// FindAgentFile looks up an agent in the project scope first and then in the
+// user scope, returning nil if no enabled or disabled copy exists
+func FindAgentFile(agentName string) (*ComponentFile, error) {
+	return findComponentFile(agentName+".md", agentScopeDirs)
+}
+
+// FindAgentFileInScope looks up an agent in one scope only
+func FindAgentFileInScope(agentName, scope string) (*ComponentFile, error) {
+	return findComponentFile(agentName+".md", onlyScope(agentScopeDirs, scope))
+}
+
+// FindCommandFile looks up a slash command, namespaced with ':' (see
+// types.CommandFilePath), in the project scope first and then in the user scope
+func FindCommandFile(commandName string) (*ComponentFile, error) {
+	return findComponentFile(filepath.FromSlash(types.CommandFilePath(commandName)), commandScopeDirs)
+}
+
+// FindCommandFileInScope looks up a slash command in one scope only
+func FindCommandFileInScope(commandName, scope string) (*ComponentFile, error) {
+	return findComponentFile(filepath.FromSlash(types.CommandFilePath(commandName)), onlyScope(commandScopeDirs, scope))
+}
+
+// onlyScope keeps the directory of one scope; an empty scope means the project
+func onlyScope(dirs []scopeDir, scope string) []scopeDir {
+	if scope == "" {
+		scope = ScopeProject
+	}
+	for _, dir := range dirs {
+		if dir.name == scope {
+			return []scopeDir{dir}
+		}
+	}
+	return nil
+}
+
+func findComponentFile(file string, scopes []scopeDir) (*ComponentFile, error) {
+	for _, scope := range scopes {
+		dir, err := scope.dirFor()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, disabled := range []bool{false, true} {
+			path := filepath.Join(dir, file)
+			if disabled {
+				path += ".disabled"
+			}
+			if _, err := os.Stat(path); err == nil {
+				return &ComponentFile{Path: path, Scope: scope.name, Disabled: disabled}, nil
+			} else if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to check %s: %w", path, err)
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// IsAgentDisabled checks if an agent exists only as a disabled (.md.disabled) file
+func IsAgentDisabled(agentName string) (bool, error) {
+	agentFile, err := FindAgentFile(agentName)
+	if err != nil {
+		return false, err
+	}
+	return agentFile != nil && agentFile.Disabled, nil
+}
+
 // IsAgentInstalled checks if an agent is installed in the project-local directory
 func IsAgentInstalled(agentName string) (bool, error) {
 	// Use project-local agents directory
@@ -86,8 +213,40 @@ func IsAgentInstalled(agentName string) (bool, error) {
 
 // ClaudeSettings represents Claude's settings.json structure
 type ClaudeSettings struct {
-	Hooks map[string]interface{} `json:"hooks,omitempty"`
+	Hooks       map[string]interface{} `json:"hooks,omitempty"`
+	Permissions *types.Permissions     `json:"permissions,omitempty"`
 	// Add other settings fields as needed
+
+	Extra map[string]json.RawMessage `json:"-"` // Keys cchp doesn't manage, written back unchanged
+}
+
+// UnmarshalJSON keeps the settings cchp doesn't know about in Extra
+func (s *ClaudeSettings) UnmarshalJSON(data []byte) error {
+	type plain ClaudeSettings
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.Extra); err != nil {
+		return err
+	}
+	delete(s.Extra, "hooks")
+	delete(s.Extra, "permissions")
+	return nil
+}
+
+// MarshalJSON writes the managed settings together with Extra
+func (s ClaudeSettings) MarshalJSON() ([]byte, error) {
+	object := make(map[string]interface{}, len(s.Extra)+1)
+	for key, value := range s.Extra {
+		object[key] = value
+	}
+	if len(s.Hooks) > 0 {
+		object["hooks"] = s.Hooks
+	}
+	if s.Permissions != nil && !s.Permissions.IsEmpty() {
+		object["permissions"] = s.Permissions
+	}
+	return marshalJSON(object, false)
 }
 
 // IsHookInstalled checks if a hook is installed in Claude's settings
@@ -313,24 +472,60 @@ func RemoveHookFromSettings(hookName string) error {
 	return writeSettingsFile(settingsPath, &settings)
 }
 
-func writeSettingsFile(path string, settings *ClaudeSettings) error {
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("failed to create settings directory: %w", err)
+// ScopeLocal is the personal project settings scope (.claude/settings.local.json), which isn't committed
+const ScopeLocal = "local"
+
+// SettingsScopes lists the settings scopes from lowest to highest precedence
+var SettingsScopes = []string{ScopeUser, ScopeProject, ScopeLocal}
+
+// GetScopedSettingsPath returns the Claude Code settings file of a scope:
+// ~/.claude/settings.json, .claude/settings.json or .claude/settings.local.json
+func GetScopedSettingsPath(scope string) (string, error) {
+	switch scope {
+	case ScopeUser:
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		return filepath.Join(homeDir, ".claude", "settings.json"), nil
+	case ScopeProject, ScopeLocal:
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get working directory: %w", err)
+		}
+		name := "settings.json"
+		if scope == ScopeLocal {
+			name = "settings.local.json"
+		}
+		return filepath.Join(wd, ".claude", name), nil
+	default:
+		return "", fmt.Errorf("invalid settings scope '%s' (use user, project or local)", scope)
 	}
+}
 
-	// Marshal settings to JSON with proper formatting
-	data, err := json.MarshalIndent(settings, "", "  ")
+// LoadSettings reads a settings file, returning empty settings if it doesn't exist
+func LoadSettings(path string) (*ClaudeSettings, error) {
+	settings := &ClaudeSettings{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to marshal settings: %w", err)
+		return nil, fmt.Errorf("failed to read settings file: %w", err)
 	}
-
-	// Write to file
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return fmt.Errorf("failed to write settings file: %w", err)
+	if err := json.Unmarshal(data, settings); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
 	}
+	return settings, nil
+}
 
-	return nil
+// SaveSettings writes a settings file, keeping the settings cchp doesn't manage
+func SaveSettings(path string, settings *ClaudeSettings) error {
+	return writeSettingsFile(path, settings)
+}
+
+func writeSettingsFile(path string, settings *ClaudeSettings) error {
+	return writeJSONFile(path, settings)
 }
 
 func containsHookName(command, hookName string) bool {
@@ -344,8 +539,10 @@ func containsHookName(command, hookName string) bool {
 	return strings.Contains(command, hookName+".py") || 
 		   strings.Contains(command, hookName+".sh") ||
 		   strings.Contains(command, hookName+".js") ||
+		   strings.Contains(command, hookName+".go") ||
 		   strings.Contains(command, "/"+hookName) ||
-		   strings.Contains(command, "\\"+hookName)
+		   strings.Contains(command, "\\"+hookName) ||
+		   strings.Contains(command, "hook run "+hookName)
 }
 
 // GetNotificationConfigPath returns the path to the notification config file
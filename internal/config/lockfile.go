@@ -0,0 +1,428 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+// GetLockfilePath returns the path to the project lockfile (.claude/cchp.lock)
+func GetLockfilePath() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return filepath.Join(wd, ".claude", "cchp.lock"), nil
+}
+
+// GetUserLockfilePath returns the path to the lockfile of components installed for
+// the user (~/.config/claude-helper/cchp.lock). They live in ~/.claude, so they
+// stay out of the project lockfile teammates sync from.
+func GetUserLockfilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "claude-helper", "cchp.lock"), nil
+}
+
+// LoadLockfile reads the project lockfile together with the user's components from
+// the user lockfile, returning an empty lockfile if neither exists
+func LoadLockfile() (*types.Lockfile, error) {
+	lockPath, err := GetLockfilePath()
+	if err != nil {
+		return nil, err
+	}
+	lock, err := readLockfile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Older versions wrote user scope entries here too; they are someone's
+	// personal components and are dropped the next time the lockfile is saved
+	for key, entry := range lock.Components {
+		if entry.Scope == ScopeUser {
+			delete(lock.Components, key)
+		}
+	}
+	for name, bundle := range lock.Bundles {
+		bundle.Components, _ = splitUserLockKeys(bundle.Components)
+		lock.Bundles[name] = bundle
+	}
+
+	userLockPath, err := GetUserLockfilePath()
+	if err != nil {
+		return nil, err
+	}
+	userLock, err := readLockfile(userLockPath)
+	if err != nil {
+		return nil, err
+	}
+	for key, entry := range userLock.Components {
+		if entry.Scope == ScopeUser {
+			lock.Components[key] = entry
+		}
+	}
+	for name, userBundle := range userLock.Bundles {
+		bundle := lock.Bundles[name]
+		if bundle.Template == "" {
+			bundle.Template = userBundle.Template
+		}
+		_, userKeys := splitUserLockKeys(userBundle.Components)
+		bundle.Components = append(bundle.Components, userKeys...)
+		lock.Bundles[name] = bundle
+	}
+	return lock, nil
+}
+
+// splitUserLockKeys separates the lock keys of components installed for the user
+func splitUserLockKeys(keys []string) (project, user []string) {
+	for _, key := range keys {
+		if _, _, scope := types.SplitLockKey(key); scope == ScopeUser {
+			user = append(user, key)
+		} else {
+			project = append(project, key)
+		}
+	}
+	return project, user
+}
+
+// readLockfile reads a single lockfile, returning an empty lockfile if it doesn't exist
+func readLockfile(lockPath string) (*types.Lockfile, error) {
+	lock := &types.Lockfile{
+		LockfileVersion: types.LockfileVersion,
+		Components:      make(map[string]types.LockedComponent),
+		Bundles:         make(map[string]types.LockedBundle),
+	}
+
+	data, err := os.ReadFile(lockPath)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile %s: %w", lockPath, err)
+	}
+
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %w", lockPath, err)
+	}
+	if lock.LockfileVersion > types.LockfileVersion {
+		return nil, fmt.Errorf("lockfile version %d is newer than supported version %d; upgrade cchp", lock.LockfileVersion, types.LockfileVersion)
+	}
+	if lock.Components == nil {
+		lock.Components = make(map[string]types.LockedComponent)
+	}
+	if lock.Bundles == nil {
+		lock.Bundles = make(map[string]types.LockedBundle)
+	}
+	if lock.LockfileVersion < 2 {
+		migrateLockKeys(lock)
+	}
+	return lock, nil
+}
+
+// migrateLockKeys rekeys a version 1 lockfile, which keyed components by bare
+// name, to the keys LockKey returns. Bundles refer to their components by key too.
+func migrateLockKeys(lock *types.Lockfile) {
+	keys := make(map[string]string, len(lock.Components))
+	components := make(map[string]types.LockedComponent, len(lock.Components))
+	for name, entry := range lock.Components {
+		key := types.LockKey(entry.Type, name, entry.Scope)
+		keys[name] = key
+		components[key] = entry
+	}
+	lock.Components = components
+
+	for bundleName, bundle := range lock.Bundles {
+		for i, name := range bundle.Components {
+			if key, ok := keys[name]; ok {
+				bundle.Components[i] = key
+			}
+		}
+		lock.Bundles[bundleName] = bundle
+	}
+}
+
+// SaveLockfile writes the project lockfile, and the user lockfile for components
+// installed for the user
+func SaveLockfile(lock *types.Lockfile) error {
+	lockPath, err := GetLockfilePath()
+	if err != nil {
+		return err
+	}
+	userLockPath, err := GetUserLockfilePath()
+	if err != nil {
+		return err
+	}
+
+	lock.LockfileVersion = types.LockfileVersion
+	project := &types.Lockfile{
+		LockfileVersion: types.LockfileVersion,
+		Components:      make(map[string]types.LockedComponent),
+		Bundles:         make(map[string]types.LockedBundle),
+	}
+	user := &types.Lockfile{
+		LockfileVersion: types.LockfileVersion,
+		Components:      make(map[string]types.LockedComponent),
+		Bundles:         make(map[string]types.LockedBundle),
+	}
+	for key, entry := range lock.Components {
+		if entry.Scope == ScopeUser {
+			user.Components[key] = entry
+		} else {
+			project.Components[key] = entry
+		}
+	}
+
+	// A bundle's user scope members are recorded with the user's components
+	for name, bundle := range lock.Bundles {
+		projectKeys, userKeys := splitUserLockKeys(bundle.Components)
+		if len(projectKeys) > 0 {
+			project.Bundles[name] = types.LockedBundle{Template: bundle.Template, Components: projectKeys}
+		}
+		if len(userKeys) > 0 {
+			user.Bundles[name] = types.LockedBundle{Template: bundle.Template, Components: userKeys}
+		}
+	}
+
+	if err := writeLockfile(lockPath, project); err != nil {
+		return err
+	}
+	return writeLockfile(userLockPath, user)
+}
+
+// writeLockfile writes a single lockfile. An empty lockfile is only written to
+// replace an existing one.
+func writeLockfile(lockPath string, lock *types.Lockfile) error {
+	if len(lock.Components) == 0 && len(lock.Bundles) == 0 {
+		if _, err := os.Stat(lockPath); os.IsNotExist(err) {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return fmt.Errorf("failed to create lockfile directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(lock); err != nil {
+		return fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+
+	if err := os.WriteFile(lockPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// RecordLockedComponent adds or replaces a component in the lockfile, under the
+// key for its type, name and scope. Template variable values already recorded
+// are kept when the entry has none.
+func RecordLockedComponent(name string, entry types.LockedComponent) error {
+	lock, err := LoadLockfile()
+	if err != nil {
+		return err
+	}
+
+	key := types.LockKey(entry.Type, name, entry.Scope)
+	if entry.Params == nil {
+		entry.Params = lock.Components[key].Params
+	}
+	if entry.Added == nil {
+		entry.Added = lock.Components[key].Added
+	}
+	lock.Components[key] = entry
+	return SaveLockfile(lock)
+}
+
+// RemoveLockedComponent drops a component from the lockfile by its lock key
+func RemoveLockedComponent(key string) error {
+	lock, err := LoadLockfile()
+	if err != nil {
+		return err
+	}
+	entry, ok := lock.Components[key]
+	if !ok {
+		return nil
+	}
+	for file := range entry.Files {
+		if err := RemoveBaseFile(file); err != nil {
+			return err
+		}
+	}
+	delete(lock.Components, key)
+	return SaveLockfile(lock)
+}
+
+// RecordLockedBundle adds or replaces a bundle in the lockfile
+func RecordLockedBundle(name string, bundle types.LockedBundle) error {
+	lock, err := LoadLockfile()
+	if err != nil {
+		return err
+	}
+	lock.Bundles[name] = bundle
+	return SaveLockfile(lock)
+}
+
+// RemoveLockedBundle drops a bundle from the lockfile
+func RemoveLockedBundle(name string) error {
+	lock, err := LoadLockfile()
+	if err != nil {
+		return err
+	}
+	if _, ok := lock.Bundles[name]; !ok {
+		return nil
+	}
+	delete(lock.Bundles, name)
+	return SaveLockfile(lock)
+}
+
+// ResolveLockedFile returns the absolute path of a file recorded in the lockfile.
+// Paths starting with ~/ are in the home directory; others are relative to the project.
+func ResolveLockedFile(file string) (string, error) {
+	if strings.HasPrefix(file, "~/") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		return filepath.Join(homeDir, filepath.FromSlash(strings.TrimPrefix(file, "~/"))), nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return filepath.Join(wd, filepath.FromSlash(file)), nil
+}
+
+// GetBaseFilesDir returns the directory holding pristine copies of installed files
+// (.claude/.cchp/base), used as the common ancestor when merging upgrades
+func GetBaseFilesDir() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return filepath.Join(wd, ".claude", ".cchp", "base"), nil
+}
+
+// getBaseFilePath returns where the pristine copy of an installed file is kept.
+// Copies of files in the home directory are kept next to the user lockfile.
+func getBaseFilePath(file string) (string, error) {
+	if rest, ok := strings.CutPrefix(file, "~/"); ok {
+		userLockPath, err := GetUserLockfilePath()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(filepath.Dir(userLockPath), "base", filepath.FromSlash(rest)), nil
+	}
+
+	baseDir, err := GetBaseFilesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, filepath.FromSlash(file)), nil
+}
+
+// SaveBaseFile stores the pristine content of an installed file.
+// file is the path recorded in the lockfile (see ResolveLockedFile).
+func SaveBaseFile(file string, data []byte) error {
+	basePath, err := getBaseFilePath(file)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(basePath), 0755); err != nil {
+		return fmt.Errorf("failed to create base files directory: %w", err)
+	}
+	if err := os.WriteFile(basePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to save base copy of %s: %w", file, err)
+	}
+	return nil
+}
+
+// LoadBaseFile returns the pristine content recorded for an installed file
+func LoadBaseFile(file string) ([]byte, error) {
+	basePath, err := getBaseFilePath(file)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(basePath)
+}
+
+// RemoveBaseFile deletes the pristine copy of an installed file
+func RemoveBaseFile(file string) error {
+	basePath, err := getBaseFilePath(file)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(basePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove base copy of %s: %w", file, err)
+	}
+	return nil
+}
+
+// LoadComponentParams returns the template variable values recorded under a lock key
+func LoadComponentParams(key string) (map[string]string, error) {
+	lock, err := LoadLockfile()
+	if err != nil {
+		return nil, err
+	}
+	if params := lock.Components[key].Params; params != nil {
+		return params, nil
+	}
+	return map[string]string{}, nil
+}
+
+// SaveComponentParams records the template variable values used for the
+// component under a lock key
+func SaveComponentParams(key string, params map[string]string) error {
+	lock, err := LoadLockfile()
+	if err != nil {
+		return err
+	}
+
+	entry := lock.Components[key]
+	entry.Params = params
+	lock.Components[key] = entry
+	return SaveLockfile(lock)
+}
+
+// SavePresetAdditions records the rules the permission preset under a lock key
+// added to its settings file
+func SavePresetAdditions(key string, added *types.Permissions) error {
+	lock, err := LoadLockfile()
+	if err != nil {
+		return err
+	}
+
+	entry := lock.Components[key]
+	entry.Added = added
+	lock.Components[key] = entry
+	return SaveLockfile(lock)
+}
+
+// HashFile returns the sha256 checksum of a file as "sha256:<hex>"
+func HashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return HashBytes(data), nil
+}
+
+// HashBytes returns the sha256 checksum of data as "sha256:<hex>"
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+// useTempProject makes an empty temporary directory the working directory,
+// with an empty home directory
+func useTempProject(t *testing.T) string {
+	t.Helper()
+
+	project := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return project
+}
+
+func TestSameNameDifferentTypes(t *testing.T) {
+	useTempProject(t)
+
+	components := []struct {
+		key   string
+		entry types.LockedComponent
+	}{
+		{"agent/lint", types.LockedComponent{Type: "agent", Files: map[string]string{".claude/agents/lint.md": "sha256:agent"}}},
+		{"hook/lint", types.LockedComponent{Type: "hook", Files: map[string]string{".claude/hooks/lint.sh": "sha256:hook"}}},
+		{"permission/lint@local", types.LockedComponent{Type: "permission", Scope: ScopeLocal}},
+	}
+	for _, c := range components {
+		if err := RecordLockedComponent("lint", c.entry); err != nil {
+			t.Fatal(err)
+		}
+		for file := range c.entry.Files {
+			if err := SaveBaseFile(file, []byte(c.key)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := RemoveLockedComponent("hook/lint"); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := LoadLockfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"agent/lint", "permission/lint@local"} {
+		if _, ok := lock.Components[key]; !ok {
+			t.Errorf("lockfile lost %s when removing hook/lint", key)
+		}
+	}
+	if _, ok := lock.Components["hook/lint"]; ok {
+		t.Errorf("lockfile still has hook/lint")
+	}
+	if base, err := LoadBaseFile(".claude/agents/lint.md"); err != nil || string(base) != "agent/lint" {
+		t.Errorf("agent base file = %q, %v, want it kept", base, err)
+	}
+	if _, err := LoadBaseFile(".claude/hooks/lint.sh"); !os.IsNotExist(err) {
+		t.Errorf("hook base file still exists: %v", err)
+	}
+}
+
+func TestLoadVersion1Lockfile(t *testing.T) {
+	project := useTempProject(t)
+
+	v1 := `{
+  "lockfile_version": 1,
+  "components": {
+    "reviewer": {"type": "agent", "template": "reviewer", "source": "built-in"},
+    "safe": {"type": "permission", "template": "safe", "source": "built-in", "scope": "local"}
+  },
+  "bundles": {
+    "essentials": {"template": "essentials", "components": ["reviewer", "safe"]}
+  }
+}
+`
+	if err := os.MkdirAll(filepath.Join(project, ".claude"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project, ".claude", "cchp.lock"), []byte(v1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := LoadLockfile()
+	if err != nil {
+		t.Fatalf("LoadLockfile() error = %v", err)
+	}
+
+	_, hasAgent := lock.Components["agent/reviewer"]
+	_, hasPreset := lock.Components["permission/safe@local"]
+	if !hasAgent || !hasPreset || len(lock.Components) != 2 {
+		t.Errorf("components = %v, want agent/reviewer and permission/safe@local", lock.Components)
+	}
+	if got, want := lock.Bundles["essentials"].Components, []string{"agent/reviewer", "permission/safe@local"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bundle components = %v, want %v", got, want)
+	}
+}
+
+func TestUserScopeStaysOutOfProjectLockfile(t *testing.T) {
+	project := useTempProject(t)
+
+	// A teammate's personal agent, written by an older version
+	v1 := `{
+  "lockfile_version": 1,
+  "components": {
+    "theirs": {"type": "agent", "template": "theirs", "source": "built-in", "scope": "user"}
+  }
+}
+`
+	lockPath := filepath.Join(project, ".claude", "cchp.lock")
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lockPath, []byte(v1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RecordLockedComponent("reviewer", types.LockedComponent{Type: "agent", Template: "reviewer"}); err != nil {
+		t.Fatal(err)
+	}
+	mine := types.LockedComponent{Type: "agent", Template: "mine", Scope: ScopeUser, Files: map[string]string{"~/.claude/agents/mine.md": "sha256:mine"}}
+	if err := RecordLockedComponent("mine", mine); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveBaseFile("~/.claude/agents/mine.md", []byte("mine")); err != nil {
+		t.Fatal(err)
+	}
+	bundle := types.LockedBundle{Template: "mix", Components: []string{"agent/reviewer", "agent/mine@user"}}
+	if err := RecordLockedBundle("mix", bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	projectLock, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"theirs", "mine"} {
+		if strings.Contains(string(projectLock), name) {
+			t.Errorf("project lockfile records the user scope agent %s:\n%s", name, projectLock)
+		}
+	}
+
+	userLockPath, err := GetUserLockfilePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	userLock, err := os.ReadFile(userLockPath)
+	if err != nil {
+		t.Fatalf("user lockfile not written: %v", err)
+	}
+	if !strings.Contains(string(userLock), "agent/mine@user") {
+		t.Errorf("user lockfile = %s, want agent/mine@user", userLock)
+	}
+	if _, err := os.Stat(filepath.Join(project, ".claude", ".cchp", "base", "home")); !os.IsNotExist(err) {
+		t.Errorf("base copy of a home directory file is kept in the project")
+	}
+
+	lock, err := LoadLockfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lock.Components["agent/mine@user"]; !ok {
+		t.Errorf("LoadLockfile() is missing the user's own agent")
+	}
+	if _, ok := lock.Components["agent/reviewer"]; !ok {
+		t.Errorf("LoadLockfile() is missing the project agent")
+	}
+	if got := lock.Bundles["mix"]; !reflect.DeepEqual(got, bundle) {
+		t.Errorf("LoadLockfile() bundle = %v, want %v", got, bundle)
+	}
+}
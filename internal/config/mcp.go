@@ -0,0 +1,123 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+// mcpServersKey holds the servers in both .mcp.json and ~/.claude.json
+const mcpServersKey = "mcpServers"
+
+// GetMCPConfigPath returns the file MCP servers of a scope are configured in:
+// the project's .mcp.json, shared through version control, or the user's ~/.claude.json
+func GetMCPConfigPath(scope string) (string, error) {
+	if scope == ScopeUser {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		return filepath.Join(homeDir, ".claude.json"), nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return filepath.Join(wd, ".mcp.json"), nil
+}
+
+// readMCPServers reads a scope's config file and its raw server entries
+func readMCPServers(scope string) (path string, file, servers map[string]json.RawMessage, err error) {
+	path, err = GetMCPConfigPath(scope)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	file, err = readJSONObject(path)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	servers = make(map[string]json.RawMessage)
+	if raw, ok := file[mcpServersKey]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &servers); err != nil {
+			return "", nil, nil, fmt.Errorf("failed to parse %s in %s: %w", mcpServersKey, path, err)
+		}
+	}
+	return path, file, servers, nil
+}
+
+// writeMCPServers stores the server entries back into the scope's config file,
+// leaving every other key as it was
+func writeMCPServers(path string, file, servers map[string]json.RawMessage) error {
+	raw, err := marshalJSON(servers, false)
+	if err != nil {
+		return fmt.Errorf("failed to encode MCP servers: %w", err)
+	}
+	file[mcpServersKey] = raw
+	return writeJSONFile(path, file)
+}
+
+// LoadMCPServers returns the MCP servers configured in a scope, keyed by name
+func LoadMCPServers(scope string) (map[string]*types.MCPServer, error) {
+	path, _, rawServers, err := readMCPServers(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := make(map[string]*types.MCPServer, len(rawServers))
+	for name, raw := range rawServers {
+		server := &types.MCPServer{}
+		if err := json.Unmarshal(raw, server); err != nil {
+			return nil, fmt.Errorf("failed to parse MCP server '%s' in %s: %w", name, path, err)
+		}
+		server.Name = name
+		servers[name] = server
+	}
+	return servers, nil
+}
+
+// FindMCPServer looks up a configured MCP server in the project scope first and
+// then in the user scope, returning a nil server if neither has it
+func FindMCPServer(name string) (*types.MCPServer, string, error) {
+	for _, scope := range []string{ScopeProject, ScopeUser} {
+		servers, err := LoadMCPServers(scope)
+		if err != nil {
+			return nil, "", err
+		}
+		if server, ok := servers[name]; ok {
+			return server, scope, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// SetMCPServer adds or replaces an MCP server in a scope's config file
+func SetMCPServer(scope string, server *types.MCPServer) error {
+	path, file, servers, err := readMCPServers(scope)
+	if err != nil {
+		return err
+	}
+	raw, err := marshalJSON(server, false)
+	if err != nil {
+		return fmt.Errorf("failed to encode MCP server '%s': %w", server.Name, err)
+	}
+	servers[server.Name] = raw
+	return writeMCPServers(path, file, servers)
+}
+
+// RemoveMCPServer removes an MCP server from a scope's config file, reporting
+// whether it was there
+func RemoveMCPServer(scope, name string) (bool, error) {
+	path, file, servers, err := readMCPServers(scope)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := servers[name]; !ok {
+		return false, nil
+	}
+	delete(servers, name)
+	return true, writeMCPServers(path, file, servers)
+}
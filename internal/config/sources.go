@@ -0,0 +1,123 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+// DefaultSourcePriority is used for sources added without an explicit priority
+const DefaultSourcePriority = 100
+
+// GetSourcesConfigPath returns the path to the user-level template sources file
+func GetSourcesConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "claude-helper", "sources.json"), nil
+}
+
+// GetSourcesCacheDir returns the directory git sources are cloned into
+func GetSourcesCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "claude-helper", "sources"), nil
+}
+
+// LoadTemplateSources returns the configured template sources sorted by priority
+func LoadTemplateSources() ([]types.TemplateSource, error) {
+	sourcesPath, err := GetSourcesConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(sourcesPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sources config: %w", err)
+	}
+
+	var sources []types.TemplateSource
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("failed to parse sources config: %w", err)
+	}
+
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Priority < sources[j].Priority
+	})
+	return sources, nil
+}
+
+// SaveTemplateSources writes the template sources config
+func SaveTemplateSources(sources []types.TemplateSource) error {
+	sourcesPath, err := GetSourcesConfigPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sourcesPath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(sources, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sources config: %w", err)
+	}
+
+	if err := os.WriteFile(sourcesPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write sources config: %w", err)
+	}
+	return nil
+}
+
+// GetSourceDir returns the local directory holding a source's files:
+// the directory itself for dir sources, or the clone in the cache for git sources
+func GetSourceDir(source types.TemplateSource) (string, error) {
+	if source.Type == types.SourceTypeDir {
+		return source.Location, nil
+	}
+
+	cacheDir, err := GetSourcesCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, source.Name), nil
+}
+
+// GetSourceTemplatesDir returns the directory containing a source's agents/ and
+// hooks/ subdirectories. Sources may keep them at the root or under templates/.
+func GetSourceTemplatesDir(source types.TemplateSource) (string, error) {
+	dir, err := GetSourceDir(source)
+	if err != nil {
+		return "", err
+	}
+
+	nested := filepath.Join(dir, "templates")
+	if info, err := os.Stat(nested); err == nil && info.IsDir() {
+		return nested, nil
+	}
+	return dir, nil
+}
+
+// FindTemplateSource returns the configured source with the given name, or nil if there is none
+func FindTemplateSource(name string) (*types.TemplateSource, error) {
+	sources, err := LoadTemplateSources()
+	if err != nil {
+		return nil, err
+	}
+	for i := range sources {
+		if sources[i].Name == name {
+			return &sources[i], nil
+		}
+	}
+	return nil, nil
+}
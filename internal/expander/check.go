@@ -0,0 +1,131 @@
+package expander
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of Issue
+const (
+	IssueOverlap        = "overlap"
+	IssueCommonWord     = "common-word"
+	IssueCLIFlag        = "cli-flag"
+	IssueNestedMarker   = "nested-marker"
+	IssueUndefinedRef   = "undefined-ref"
+	IssueUnknownCommand = "unknown-command"
+)
+
+// Issue is a problem with a set of mappings found by Check
+type Issue struct {
+	Marker  string
+	Kind    string
+	Error   bool // The mapping doesn't work as written; otherwise it's a warning
+	Message string
+}
+
+// commonWords are words people type in prompts often enough that a marker
+// spelled the same way would expand by accident
+var commonWords = wordSet(`a about add after again all also an and any api app are as at be because
+before build bug but by can change check code commit config could create data debug delete deploy
+diff do docs does done error explain file find first fix for from function get go has have help
+here how i if in into is it just know like list log make me more my new no not now of on one only
+or other our out please pr readme refactor remove review run see should so some test that the
+their them then there these this to todo too up update use want was we what when where which why
+will with would write you your`)
+
+// commonFlags are command line flags that show up in pasted commands
+var commonFlags = wordSet(`-a -al -d -e -f -fr -h -i -l -la -lh -m -n -o -p -q -r -rf -s -t -u -v -vv -x
+--all --debug --dry-run --force --global --help --json --output --quiet --recursive --save-dev
+--verbose --version`)
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// Check reports markers that overlap each other, markers that are common words
+// or command line flags, and expansions whose placeholders refer to markers or
+// commands that don't work. Issues are sorted by marker.
+func Check(mappings, commands map[string]string) []Issue {
+	markers := make([]string, 0, len(mappings))
+	for marker := range mappings {
+		markers = append(markers, marker)
+	}
+	sort.Strings(markers)
+
+	var issues []Issue
+	for i, marker := range markers {
+		for _, other := range markers[i+1:] {
+			if message := overlap(marker, other); message != "" {
+				issues = append(issues, Issue{Marker: marker, Kind: IssueOverlap, Message: message})
+			}
+		}
+
+		lower := strings.ToLower(marker)
+		if commonFlags[lower] {
+			issues = append(issues, Issue{Marker: marker, Kind: IssueCLIFlag,
+				Message: fmt.Sprintf("%s is a common command line flag; it expands in prompts that mention commands using it", marker)})
+		} else if commonWords[lower] {
+			issues = append(issues, Issue{Marker: marker, Kind: IssueCommonWord,
+				Message: fmt.Sprintf("%s is a common word; it expands whenever a prompt uses it", marker)})
+		}
+
+		issues = append(issues, checkReferences(marker, mappings, commands)...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Marker < issues[j].Marker })
+	return issues
+}
+
+// overlap describes how two markers can be confused, or returns ""
+func overlap(a, b string) string {
+	switch {
+	case strings.EqualFold(a, b):
+		return fmt.Sprintf("%s and %s differ only in case", a, b)
+	case strings.TrimLeft(a, "-") == strings.TrimLeft(b, "-"):
+		return fmt.Sprintf("%s and %s differ only in their dashes", a, b)
+	case strings.HasPrefix(b, a):
+		return fmt.Sprintf("%s is a prefix of %s; %s only expands when typed as a whole word", a, b, a)
+	}
+	return ""
+}
+
+// checkReferences reports {{...}} in an expansion that will be kept literally or fail
+func checkReferences(marker string, mappings, commands map[string]string) []Issue {
+	var issues []Issue
+	rest := mappings[marker]
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start+2:], "}}")
+		if end < 0 {
+			break
+		}
+		source := rest[start : start+2+end+2]
+		rest = rest[start+len(source):]
+
+		name, param, _ := strings.Cut(strings.TrimSpace(source[2:len(source)-2]), ":")
+		name, param = strings.TrimSpace(name), strings.TrimSpace(param)
+		switch {
+		case name == "cmd":
+			if _, ok := commands[param]; !ok && !strings.Contains(param, "$") {
+				issues = append(issues, Issue{Marker: marker, Kind: IssueUnknownCommand, Error: true,
+					Message: fmt.Sprintf("%s uses %s, but '%s' is not in commands", marker, source, param)})
+			}
+		case isPlaceholder(name):
+		case mappings[name] != "":
+			issues = append(issues, Issue{Marker: marker, Kind: IssueNestedMarker,
+				Message: fmt.Sprintf("%s references %s; expansions are not expanded again, so it stays literal", marker, source)})
+		default:
+			issues = append(issues, Issue{Marker: marker, Kind: IssueUndefinedRef, Error: true,
+				Message: fmt.Sprintf("%s references %s, which is not a marker or placeholder", marker, source)})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,46 @@
+package expander
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings map[string]string
+		commands map[string]string
+		want     []string // Kinds of the issues found, in order
+		errors   int
+	}{
+		{name: "no problems", mappings: map[string]string{"-zz": "x", "--explain-more": "y"}},
+		{name: "prefix", mappings: map[string]string{"-zz": "x", "-zzz": "y"}, want: []string{IssueOverlap}},
+		{name: "case", mappings: map[string]string{"Zed": "x", "zed": "y"}, want: []string{IssueOverlap}},
+		{name: "dashes", mappings: map[string]string{"-zz": "x", "--zz": "y"}, want: []string{IssueOverlap}},
+		{name: "common word", mappings: map[string]string{"fix": "x"}, want: []string{IssueCommonWord}},
+		{name: "common flag", mappings: map[string]string{"-rf": "x"}, want: []string{IssueCLIFlag}},
+		{name: "placeholders are fine", mappings: map[string]string{"-zz": "{{1}} {{date}} {{file:$1}} {{cmd:log}}"}, commands: map[string]string{"log": "git log"}},
+		{name: "nested marker", mappings: map[string]string{"-zz": "see {{-yy}}", "-yy": "y"}, want: []string{IssueNestedMarker}},
+		{name: "undefined reference", mappings: map[string]string{"-zz": "see {{-nope}}"}, want: []string{IssueUndefinedRef}, errors: 1},
+		{name: "unknown command", mappings: map[string]string{"-zz": "{{cmd:rm}}"}, want: []string{IssueUnknownCommand}, errors: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kinds []string
+			errors := 0
+			for _, issue := range Check(tt.mappings, tt.commands) {
+				kinds = append(kinds, issue.Kind)
+				if issue.Error {
+					errors++
+				}
+			}
+			if !reflect.DeepEqual(kinds, tt.want) {
+				t.Errorf("Check() kinds = %v, want %v", kinds, tt.want)
+			}
+			if errors != tt.errors {
+				t.Errorf("Check() errors = %d, want %d", errors, tt.errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,402 @@
+// Package expander expands text expander markers in prompts.
+//
+// Markers only match as whole words: the characters around a marker must not be
+// letters, digits, '_' or '-' (CJK characters count as boundaries, since those
+// scripts don't separate words with spaces). When several markers match at the
+// same position the longest wins, so "-dd" is never read as "-d" followed by "d".
+//
+// A marker preceded by the escape character is kept literally. Escape characters
+// before a marker are halved, with an odd count escaping the marker:
+//
+//	\m    -> m
+//	\\m   -> \ + expansion
+//	\\\m  -> \ + m
+//	\\\\m -> \\ + expansion
+//
+// Expansions can contain placeholders. A snippet that uses {{args}} or {{1}},
+// {{2}}... takes arguments in parentheses right after the marker, separated by
+// commas: with "-t" mapped to "write tests for {{1}}", "-t(src/foo.go)" expands to
+// "write tests for src/foo.go". Such markers are left alone when used without
+// arguments. The dynamic placeholders {{date}}, {{time}}, {{branch}},
+// {{file:path}} and {{cmd:name}} are rendered by a Resolver (see Values); in
+// their parameter $args and $1, $2... stand for the marker's arguments. Any
+// other {{...}} is kept as written.
+package expander
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultEscapeChar is used when a config doesn't set escape_char
+const DefaultEscapeChar = '\\'
+
+// Match is a marker that was expanded
+type Match struct {
+	Marker string
+	Offset int      // Byte offset of the marker in the input
+	Args   []string // Arguments given in parentheses after the marker
+	Err    error    // Placeholders that couldn't be rendered; they expand to nothing
+}
+
+// Resolver renders the dynamic placeholders in expansions
+type Resolver interface {
+	Resolve(name, param string) (string, error)
+}
+
+// Dynamic placeholders, rendered by a Resolver
+var dynamicPlaceholders = map[string]bool{
+	"date":   true,
+	"time":   true,
+	"branch": true,
+	"file":   true,
+	"cmd":    true,
+}
+
+// Expander expands a fixed set of markers
+type Expander struct {
+	snippets map[string]*snippet
+	markers  []string // Longest first, then alphabetical, for deterministic matching
+	escape   rune
+	resolver Resolver
+}
+
+// snippet is a parsed expansion
+type snippet struct {
+	parts     []part
+	takesArgs bool
+}
+
+// part is literal text, or a placeholder when name is set
+type part struct {
+	text  string
+	name  string
+	param string
+}
+
+// New returns an Expander for mappings. Only the first character of escapeChar
+// is used; an empty escapeChar means a backslash.
+func New(mappings map[string]string, escapeChar string) *Expander {
+	e := &Expander{
+		snippets: make(map[string]*snippet, len(mappings)),
+		escape:   DefaultEscapeChar,
+	}
+	if r, _ := utf8.DecodeRuneInString(escapeChar); escapeChar != "" && r != utf8.RuneError {
+		e.escape = r
+	}
+
+	for marker, replacement := range mappings {
+		if marker == "" {
+			continue
+		}
+		e.snippets[marker] = parseSnippet(replacement)
+		e.markers = append(e.markers, marker)
+	}
+	sort.Slice(e.markers, func(i, j int) bool {
+		if len(e.markers[i]) != len(e.markers[j]) {
+			return len(e.markers[i]) > len(e.markers[j])
+		}
+		return e.markers[i] < e.markers[j]
+	})
+	return e
+}
+
+// WithResolver sets the Resolver for dynamic placeholders. Without one they
+// expand to nothing and are reported in Match.Err.
+func (e *Expander) WithResolver(r Resolver) *Expander {
+	e.resolver = r
+	return e
+}
+
+// Expand replaces every marker in text with its expansion in a single pass;
+// expansions, including their arguments and dynamic values, are not expanded
+// again. It returns the markers that were expanded.
+func (e *Expander) Expand(text string) (string, []Match) {
+	if len(e.markers) == 0 {
+		return text, nil
+	}
+
+	var b strings.Builder
+	var matches []Match
+	escapeSize := utf8.RuneLen(e.escape)
+
+	for i := 0; i < len(text); {
+		// A run of escape characters followed by a marker
+		if run := e.escapeRun(text[i:]); run > 0 {
+			start := i + run*escapeSize
+			marker := e.matchAt(text, start)
+			if marker == "" {
+				b.WriteString(text[i:start])
+				i = start
+				continue
+			}
+			end := start + len(marker)
+			if !boundaryBefore(text, i) {
+				b.WriteString(text[i:end])
+				i = end
+				continue
+			}
+
+			b.WriteString(strings.Repeat(string(e.escape), run/2))
+			if run%2 == 1 {
+				b.WriteString(marker)
+				i = end
+				continue
+			}
+			if expansion, match, next, ok := e.expandAt(text, marker, start); ok {
+				b.WriteString(expansion)
+				matches = append(matches, match)
+				i = next
+			} else {
+				b.WriteString(marker)
+				i = end
+			}
+			continue
+		}
+
+		if marker := e.matchAt(text, i); marker != "" && boundaryBefore(text, i) {
+			if expansion, match, next, ok := e.expandAt(text, marker, i); ok {
+				b.WriteString(expansion)
+				matches = append(matches, match)
+				i = next
+				continue
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(text[i:])
+		b.WriteString(text[i : i+size])
+		i += size
+	}
+
+	return b.String(), matches
+}
+
+// expandAt renders marker found at offset, reading its arguments if it takes any.
+// It returns the offset after the marker and its arguments, and false for a marker
+// that takes arguments but has none.
+func (e *Expander) expandAt(text, marker string, offset int) (string, Match, int, bool) {
+	s := e.snippets[marker]
+	match := Match{Marker: marker, Offset: offset}
+	next := offset + len(marker)
+
+	raw := ""
+	if s.takesArgs {
+		var ok bool
+		raw, next, ok = readArgs(text, next)
+		if !ok {
+			return "", Match{}, 0, false
+		}
+		for _, arg := range strings.Split(raw, ",") {
+			match.Args = append(match.Args, strings.TrimSpace(arg))
+		}
+	}
+
+	var b strings.Builder
+	var errs []error
+	for _, p := range s.parts {
+		if p.name == "" {
+			b.WriteString(p.text)
+			continue
+		}
+		value, err := e.renderPlaceholder(p, strings.TrimSpace(raw), match.Args)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.text, err))
+			continue
+		}
+		b.WriteString(value)
+	}
+	match.Err = errors.Join(errs...)
+	return b.String(), match, next, true
+}
+
+func (e *Expander) renderPlaceholder(p part, raw string, args []string) (string, error) {
+	if p.name == "args" {
+		return raw, nil
+	}
+	if n, err := strconv.Atoi(p.name); err == nil {
+		if n > len(args) || args[n-1] == "" {
+			return "", fmt.Errorf("argument %d is missing", n)
+		}
+		return args[n-1], nil
+	}
+	if e.resolver == nil {
+		return "", errors.New("dynamic values are not available")
+	}
+	return e.resolver.Resolve(p.name, substituteArgs(p.param, raw, args))
+}
+
+// readArgs reads "(...)" at offset, allowing nested parentheses. It returns the
+// text inside and the offset after the closing parenthesis.
+func readArgs(text string, offset int) (string, int, bool) {
+	if offset >= len(text) || text[offset] != '(' {
+		return "", offset, false
+	}
+	depth := 0
+	for i := offset; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return text[offset+1 : i], i + 1, true
+			}
+		}
+	}
+	return "", offset, false
+}
+
+// substituteArgs replaces $args and $1, $2... in a placeholder parameter
+func substituteArgs(param, raw string, args []string) string {
+	if !strings.Contains(param, "$") {
+		return param
+	}
+	var b strings.Builder
+	for i := 0; i < len(param); i++ {
+		if param[i] != '$' {
+			b.WriteByte(param[i])
+			continue
+		}
+		if strings.HasPrefix(param[i+1:], "args") {
+			b.WriteString(raw)
+			i += len("args")
+			continue
+		}
+		j := i + 1
+		for j < len(param) && param[j] >= '0' && param[j] <= '9' {
+			j++
+		}
+		if j == i+1 {
+			b.WriteByte('$')
+			continue
+		}
+		if n, _ := strconv.Atoi(param[i+1 : j]); n >= 1 && n <= len(args) {
+			b.WriteString(args[n-1])
+		}
+		i = j - 1
+	}
+	return b.String()
+}
+
+// parseSnippet splits an expansion into literal text and placeholders
+func parseSnippet(expansion string) *snippet {
+	s := &snippet{}
+	literal := ""
+	rest := expansion
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start+2:], "}}")
+		if end < 0 {
+			break
+		}
+		source := rest[start : start+2+end+2]
+		name, param, _ := strings.Cut(strings.TrimSpace(source[2:len(source)-2]), ":")
+		name = strings.TrimSpace(name)
+
+		literal += rest[:start]
+		rest = rest[start+len(source):]
+		if !isPlaceholder(name) {
+			literal += source
+			continue
+		}
+
+		if literal != "" {
+			s.parts = append(s.parts, part{text: literal})
+			literal = ""
+		}
+		s.parts = append(s.parts, part{text: source, name: name, param: strings.TrimSpace(param)})
+		if name == "args" || isArgIndex(name) || strings.Contains(param, "$") {
+			s.takesArgs = true
+		}
+	}
+	literal += rest
+	if literal != "" {
+		s.parts = append(s.parts, part{text: literal})
+	}
+	return s
+}
+
+func isPlaceholder(name string) bool {
+	return name == "args" || isArgIndex(name) || dynamicPlaceholders[name]
+}
+
+func isArgIndex(name string) bool {
+	n, err := strconv.Atoi(name)
+	return err == nil && n >= 1 && strconv.Itoa(n) == name
+}
+
+// escapeRun returns how many escape characters text starts with
+func (e *Expander) escapeRun(text string) int {
+	count := 0
+	for _, r := range text {
+		if r != e.escape {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// matchAt returns the longest marker starting at offset that ends on a word boundary
+func (e *Expander) matchAt(text string, offset int) string {
+	for _, marker := range e.markers {
+		if strings.HasPrefix(text[offset:], marker) && boundaryAfter(text, offset+len(marker)) {
+			return marker
+		}
+	}
+	return ""
+}
+
+func boundaryBefore(text string, offset int) bool {
+	if offset == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:offset])
+	return !isWordRune(r)
+}
+
+func boundaryAfter(text string, offset int) bool {
+	if offset >= len(text) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(text[offset:])
+	return !isWordRune(r)
+}
+
+// isWordRune reports whether r continues a word that a marker can't be part of
+func isWordRune(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+}
+
+// IsValidMarker reports whether marker can be used as a text expander marker:
+// a flag such as -d or --explain, or a word made of letters, digits, '_' and '-'
+func IsValidMarker(marker string) bool {
+	if len(marker) == 0 {
+		return false
+	}
+
+	// Allow markers starting with - or -- or just alphanumeric words
+	if strings.HasPrefix(marker, "-") {
+		return len(marker) > 1
+	}
+
+	// Allow simple word markers
+	for _, char := range marker {
+		if !((char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') ||
+			(char >= '0' && char <= '9') || char == '_' || char == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+package expander
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	mappings := map[string]string{
+		"-d":     "DETAIL",
+		"-dd":    "DOUBLE",
+		"--long": "LONG",
+		"fix":    "FIX IT",
+		"-r":     "uses -d",
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		markers []string
+	}{
+		{name: "no markers", text: "hello world", want: "hello world"},
+		{name: "single marker", text: "explain -d", want: "explain DETAIL", markers: []string{"-d"}},
+		{name: "marker at start", text: "-d please", want: "DETAIL please", markers: []string{"-d"}},
+		{name: "marker alone", text: "-d", want: "DETAIL", markers: []string{"-d"}},
+		{name: "longest match wins", text: "explain -dd", want: "explain DOUBLE", markers: []string{"-dd"}},
+		{name: "both lengths", text: "-d and -dd", want: "DETAIL and DOUBLE", markers: []string{"-d", "-dd"}},
+		{name: "double dash marker", text: "be --long", want: "be LONG", markers: []string{"--long"}},
+		{name: "word marker", text: "please fix", want: "please FIX IT", markers: []string{"fix"}},
+		{name: "not inside a word", text: "prefix fixed suffix", want: "prefix fixed suffix"},
+		{name: "not after a letter", text: "build-d", want: "build-d"},
+		{name: "not before a letter", text: "run -debug", want: "run -debug"},
+		{name: "not part of a longer flag", text: "--d", want: "--d"},
+		{name: "punctuation is a boundary", text: "(-d), fix.", want: "(DETAIL), FIX IT.", markers: []string{"-d", "fix"}},
+		{name: "newline is a boundary", text: "a\n-d\n", want: "a\nDETAIL\n", markers: []string{"-d"}},
+		{name: "CJK is a boundary", text: "解释-d代码", want: "解释DETAIL代码", markers: []string{"-d"}},
+		{name: "expansions are not expanded again", text: "-r", want: "uses -d", markers: []string{"-r"}},
+		{name: "escaped marker", text: `keep \-d`, want: "keep -d"},
+		{name: "escaped backslash then marker", text: `\\-d`, want: `\DETAIL`, markers: []string{"-d"}},
+		{name: "escaped backslash and marker", text: `\\\-d`, want: `\-d`},
+		{name: "two escaped backslashes then marker", text: `\\\\-d`, want: `\\DETAIL`, markers: []string{"-d"}},
+		{name: "backslash without marker", text: `C:\path \n`, want: `C:\path \n`},
+		{name: "escape inside a word", text: `path\-d`, want: `path\-d`},
+	}
+
+	e := New(mappings, "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, matches := e.Expand(tt.text)
+			if got != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			var markers []string
+			for _, match := range matches {
+				markers = append(markers, match.Marker)
+			}
+			if !reflect.DeepEqual(markers, tt.markers) {
+				t.Errorf("Expand(%q) matched %v, want %v", tt.text, markers, tt.markers)
+			}
+		})
+	}
+}
+
+func TestExpandEscapeChar(t *testing.T) {
+	mappings := map[string]string{"-d": "DETAIL"}
+
+	tests := []struct {
+		name   string
+		escape string
+		text   string
+		want   string
+	}{
+		{name: "default is backslash", escape: "", text: `\-d -d`, want: "-d DETAIL"},
+		{name: "custom escape", escape: "!", text: "!-d -d", want: "-d DETAIL"},
+		{name: "custom escape doubled", escape: "!", text: "!!-d", want: "!DETAIL"},
+		{name: "backslash is literal with custom escape", escape: "!", text: `\-d`, want: `\DETAIL`},
+		{name: "multibyte escape", escape: "§", text: "§-d", want: "-d"},
+		{name: "only first character used", escape: "!?", text: "!-d ?-d", want: "-d ?DETAIL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := New(mappings, tt.escape).Expand(tt.text)
+			if got != tt.want {
+				t.Errorf("Expand(%q) with escape %q = %q, want %q", tt.text, tt.escape, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandOffsets(t *testing.T) {
+	_, matches := New(map[string]string{"-d": "x"}, "").Expand(`é -d \\-d`)
+	want := []Match{{Marker: "-d", Offset: 3}, {Marker: "-d", Offset: 8}}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("matches = %+v, want %+v", matches, want)
+	}
+}
+
+func TestExpandArgs(t *testing.T) {
+	mappings := map[string]string{
+		"-t":   "write table-driven tests for {{1}}",
+		"-cmp": "compare {{1}} with {{2}}",
+		"-all": "look at {{args}}",
+		"-opt": "{{1}} then {{2}}",
+		"-d":   "DETAIL",
+		"-lit": "keep {{unknown}} and {{1",
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+		args [][]string
+	}{
+		{name: "single argument", text: "-t(src/foo.go) please", want: "write table-driven tests for src/foo.go please", args: [][]string{{"src/foo.go"}}},
+		{name: "several arguments", text: "-cmp(a.go, b.go)", want: "compare a.go with b.go", args: [][]string{{"a.go", "b.go"}}},
+		{name: "raw arguments", text: "-all(x, y)", want: "look at x, y", args: [][]string{{"x", "y"}}},
+		{name: "nested parentheses", text: "-t(f(x))", want: "write table-driven tests for f(x)", args: [][]string{{"f(x)"}}},
+		{name: "missing arguments keep the marker", text: "-t alone", want: "-t alone"},
+		{name: "unclosed arguments keep the marker", text: "-t(src", want: "-t(src"},
+		{name: "escaped marker keeps its arguments", text: `\-t(x)`, want: "-t(x)"},
+		{name: "arguments are not expanded", text: "-t(-d)", want: "write table-driven tests for -d", args: [][]string{{"-d"}}},
+		{name: "plain marker ignores parentheses", text: "-d(note)", want: "DETAIL(note)", args: [][]string{nil}},
+		{name: "unknown placeholders are literal", text: "-lit", want: "keep {{unknown}} and {{1", args: [][]string{nil}},
+	}
+
+	e := New(mappings, "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, matches := e.Expand(tt.text)
+			if got != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			var args [][]string
+			for _, match := range matches {
+				args = append(args, match.Args)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("Expand(%q) args = %q, want %q", tt.text, args, tt.args)
+			}
+		})
+	}
+
+	_, matches := e.Expand("-opt(one)")
+	if len(matches) != 1 || matches[0].Err == nil || !strings.Contains(matches[0].Err.Error(), "argument 2 is missing") {
+		t.Errorf("missing argument should be reported, got %+v", matches)
+	}
+}
+
+type fakeResolver map[string]string
+
+func (r fakeResolver) Resolve(name, param string) (string, error) {
+	value, ok := r[name+":"+param]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return value, nil
+}
+
+func TestExpandDynamic(t *testing.T) {
+	mappings := map[string]string{
+		"-b":  "on {{branch}}",
+		"-f":  "file: {{file:$1}}",
+		"-s":  "{{cmd:status}} / {{cmd:missing}}",
+		"-ts": "{{ date : 2006 }}",
+	}
+	resolver := fakeResolver{
+		"branch:":        "main",
+		"file:README.md": "# Readme",
+		"cmd:status":     "clean",
+		"date:2006":      "2026",
+	}
+
+	tests := []struct {
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{text: "-b", want: "on main"},
+		{text: "-f(README.md)", want: "file: # Readme"},
+		{text: "-f(other.md)", want: "file: ", wantErr: true},
+		{text: "-s", want: "clean / ", wantErr: true},
+		{text: "-ts", want: "2026"},
+	}
+
+	e := New(mappings, "").WithResolver(resolver)
+	for _, tt := range tests {
+		got, matches := e.Expand(tt.text)
+		if got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+		if len(matches) != 1 || (matches[0].Err != nil) != tt.wantErr {
+			t.Errorf("Expand(%q) matches = %+v, want error %v", tt.text, matches, tt.wantErr)
+		}
+	}
+
+	got, matches := New(mappings, "").Expand("-b")
+	if got != "on " || len(matches) != 1 || matches[0].Err == nil {
+		t.Errorf("without a resolver Expand(-b) = %q, %+v", got, matches)
+	}
+}
+
+func TestIsValidMarker(t *testing.T) {
+	tests := []struct {
+		marker string
+		want   bool
+	}{
+		{"-d", true},
+		{"--explain", true},
+		{"debug", true},
+		{"my_marker-2", true},
+		{"", false},
+		{"-", false},
+		{"two words", false},
+		{"emoji🙂", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValidMarker(tt.marker); got != tt.want {
+			t.Errorf("IsValidMarker(%q) = %v, want %v", tt.marker, got, tt.want)
+		}
+	}
+}
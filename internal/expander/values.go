@@ -0,0 +1,143 @@
+package expander
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Limits on dynamic values, so a snippet can't stall or flood a prompt
+const (
+	DefaultCommandTimeout = 3 * time.Second
+	MaxValueBytes         = 64 * 1024
+)
+
+// Values resolves the built-in dynamic placeholders for a project:
+//
+//	{{date}}, {{date:<Go layout>}}  current date, 2006-01-02 by default
+//	{{time}}, {{time:<Go layout>}}  current time, 15:04 by default
+//	{{branch}}                      current git branch
+//	{{file:path}}                   contents of a file inside the project
+//	{{cmd:name}}                    output of the command allowlisted as name
+//
+// Commands are run without a shell and only ever come from Commands, never
+// from the prompt.
+type Values struct {
+	Dir      string            // Project root; files must be inside it and commands run in it
+	Commands map[string]string // Allowlisted commands for {{cmd:name}}
+	Timeout  time.Duration     // Per command, DefaultCommandTimeout when zero
+	Now      func() time.Time  // time.Now when nil
+}
+
+// Resolve renders one dynamic placeholder
+func (v *Values) Resolve(name, param string) (string, error) {
+	switch name {
+	case "date":
+		return v.now().Format(layoutOr(param, "2006-01-02")), nil
+	case "time":
+		return v.now().Format(layoutOr(param, "15:04")), nil
+	case "branch":
+		return v.run("git", "branch", "--show-current")
+	case "file":
+		return v.readFile(param)
+	case "cmd":
+		command, ok := v.Commands[param]
+		if !ok {
+			return "", fmt.Errorf("command '%s' is not in the allowlist", param)
+		}
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			return "", fmt.Errorf("command '%s' is empty", param)
+		}
+		return v.run(args[0], args[1:]...)
+	default:
+		return "", fmt.Errorf("unknown placeholder '%s'", name)
+	}
+}
+
+func (v *Values) now() time.Time {
+	if v.Now != nil {
+		return v.Now()
+	}
+	return time.Now()
+}
+
+func layoutOr(layout, fallback string) string {
+	if layout == "" {
+		return fallback
+	}
+	return layout
+}
+
+// readFile reads a file inside the project, refusing paths that leave it
+func (v *Values) readFile(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("no file given")
+	}
+	root, err := filepath.EvalSymlinks(v.Dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if rel, err := filepath.Rel(root, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the project", name)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, MaxValueBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return truncate(data), nil
+}
+
+// run runs a command in the project directory and returns its trimmed output
+func (v *Values) run(name string, args ...string) (string, error) {
+	timeout := v.Timeout
+	if timeout == 0 {
+		timeout = DefaultCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = v.Dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("%s timed out after %s", name, timeout)
+		}
+		if msg, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); msg != "" {
+			return "", fmt.Errorf("%s failed: %s", name, msg)
+		}
+		return "", fmt.Errorf("%s failed: %w", name, err)
+	}
+	return strings.TrimSpace(truncate(stdout.Bytes())), nil
+}
+
+func truncate(data []byte) string {
+	if len(data) <= MaxValueBytes {
+		return string(data)
+	}
+	return strings.ToValidUTF8(string(data[:MaxValueBytes]), "") + "\n... (truncated)"
+}
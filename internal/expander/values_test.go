@@ -0,0 +1,79 @@
+package expander
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValuesResolve(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	values := &Values{
+		Dir:      dir,
+		Commands: map[string]string{"hello": "echo hi there", "empty": " "},
+		Now:      func() time.Time { return time.Date(2026, 3, 4, 5, 6, 0, 0, time.UTC) },
+	}
+
+	tests := []struct {
+		name    string
+		param   string
+		want    string
+		wantErr string
+	}{
+		{name: "date", want: "2026-03-04"},
+		{name: "date", param: "Jan 2", want: "Mar 4"},
+		{name: "time", want: "05:06"},
+		{name: "file", param: "notes.txt", want: "hello\n"},
+		{name: "file", param: "missing.txt", wantErr: "failed to read"},
+		{name: "file", param: "../" + filepath.Base(filepath.Dir(outside)) + "/secret.txt", wantErr: "outside the project"},
+		{name: "file", param: outside, wantErr: "outside the project"},
+		{name: "file", wantErr: "no file given"},
+		{name: "cmd", param: "hello", want: "hi there"},
+		{name: "cmd", param: "rm", wantErr: "not in the allowlist"},
+		{name: "cmd", param: "empty", wantErr: "is empty"},
+		{name: "nope", wantErr: "unknown placeholder"},
+	}
+
+	for _, tt := range tests {
+		if tt.name == "cmd" && tt.want != "" && runtime.GOOS == "windows" {
+			continue
+		}
+		got, err := values.Resolve(tt.name, tt.param)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve(%q, %q) error = %v, want %q", tt.name, tt.param, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q, %q) = %q, %v, want %q", tt.name, tt.param, got, err, tt.want)
+		}
+	}
+}
+
+func TestValuesTruncate(t *testing.T) {
+	dir := t.TempDir()
+	big := strings.Repeat("x", MaxValueBytes+10)
+	if err := os.WriteFile(filepath.Join(dir, "big.txt"), []byte(big), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := (&Values{Dir: dir}).Resolve("file", "big.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(got, "(truncated)") || len(got) > MaxValueBytes+len("\n... (truncated)") {
+		t.Errorf("file value was not truncated, got %d bytes", len(got))
+	}
+}
@@ -0,0 +1,180 @@
+package manifest
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+	"github.com/zxj777/claude-helper/pkg/types"
+)
+
+// SignatureSuffix is appended to a manifest file's name to find its signature
+const SignatureSuffix = ".sig"
+
+// KeyPrefix optionally prefixes base64 ed25519 public keys
+const KeyPrefix = "ed25519:"
+
+// hookScriptExtensions are the script files copied alongside a hook template
+var hookScriptExtensions = []string{".py", ".sh", ".js", ".ts", ".go"}
+
+// Locate returns the manifest file that applies to a template, relative to its
+// template directory: its <name>.manifest.yaml, or the directory's index.yaml.
+// It returns an empty path if there is neither.
+func Locate(template *assets.Template) (string, error) {
+	manifestPath := template.ManifestPath()
+	if _, err := fs.Stat(template.FS, manifestPath); err == nil {
+		return manifestPath, nil
+	}
+
+	if _, err := fs.Stat(template.FS, IndexFile); err == nil {
+		return IndexFile, nil
+	}
+	return "", nil
+}
+
+// ComponentFiles returns the files installing a template reads, relative to the template root
+func ComponentFiles(template *assets.Template) ([]string, error) {
+	files := []string{template.Path}
+
+	if template.Type == "hook" {
+		dir := path.Dir(template.Path)
+		for _, ext := range hookScriptExtensions {
+			script := path.Join(dir, template.Name+ext)
+			if _, err := fs.Stat(template.FS, script); err == nil {
+				files = append(files, script)
+			}
+		}
+
+		// Files the hook copies from its template directory, resolved like Template.ReadSibling
+		data, err := template.ReadFile()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", template.Path, err)
+		}
+		hook, err := types.ParseLegacyHook(data, types.HookFormatForPath(template.Path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", template.Path, err)
+		}
+		for _, file := range hook.Files {
+			if file.Source != "" {
+				files = append(files, path.Join(dir, filepath.ToSlash(file.Source)))
+			}
+		}
+	}
+
+	sort.Strings(files)
+	return slices.Compact(files), nil
+}
+
+// ContentHash returns a checksum over every file installing a template reads,
+// so a lockfile can tell whether the template changed since it was installed
+func ContentHash(template *assets.Template) (string, error) {
+	files, err := ComponentFiles(template)
+	if err != nil {
+		return "", err
+	}
+
+	var content []byte
+	for _, file := range files {
+		data, err := fs.ReadFile(template.FS, file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		// Paths are hashed too, so moving content between files changes the hash
+		content = append(content, file...)
+		content = append(content, 0)
+		content = append(content, config.HashBytes(data)...)
+		content = append(content, '\n')
+	}
+	return config.HashBytes(content), nil
+}
+
+// VerifyChecksums checks every file a template installs against the manifest's
+// checksums. Files without a checksum are reported as unverified.
+func VerifyChecksums(template *assets.Template, checksums map[string]string) ([]string, error) {
+	files, err := ComponentFiles(template)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, file := range files {
+		expected, ok := checksums[file]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s has no checksum in the manifest", file))
+			continue
+		}
+
+		data, err := fs.ReadFile(template.FS, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if normalizeChecksum(expected) != config.HashBytes(data) {
+			problems = append(problems, fmt.Sprintf("%s does not match its checksum", file))
+		}
+	}
+	return problems, nil
+}
+
+// normalizeChecksum accepts bare hex digests as well as "sha256:<hex>"
+func normalizeChecksum(checksum string) string {
+	checksum = strings.ToLower(strings.TrimSpace(checksum))
+	if !strings.Contains(checksum, ":") {
+		return "sha256:" + checksum
+	}
+	return checksum
+}
+
+// ParsePublicKey decodes a base64 ed25519 public key, optionally prefixed with "ed25519:"
+func ParsePublicKey(key string) (ed25519.PublicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(strings.TrimSpace(key), KeyPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(data) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key: expected %d bytes, got %d", ed25519.PublicKeySize, len(data))
+	}
+	return ed25519.PublicKey(data), nil
+}
+
+// VerifySignature checks that manifestPath + ".sig" in fsys holds a base64 ed25519
+// signature of the manifest file made by one of the trusted keys
+func VerifySignature(fsys fs.FS, manifestPath string, trustedKeys []string) error {
+	data, err := fs.ReadFile(fsys, manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	sigPath := manifestPath + SignatureSuffix
+	sigData, err := fs.ReadFile(fsys, sigPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("manifest %s is not signed (missing %s)", path.Base(manifestPath), path.Base(sigPath))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read signature: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sigData)))
+	if err != nil {
+		return fmt.Errorf("invalid signature %s: %w", sigPath, err)
+	}
+
+	for _, key := range trustedKeys {
+		publicKey, err := ParsePublicKey(key)
+		if err != nil {
+			continue
+		}
+		if ed25519.Verify(publicKey, data, signature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature of %s does not match any trusted key", path.Base(manifestPath))
+}
@@ -0,0 +1,208 @@
+package manifest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/internal/config"
+)
+
+func TestVerifySignature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := []byte("version: 1.0.0\n")
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, manifest))
+	trusted := base64.StdEncoding.EncodeToString(publicKey)
+	untrusted := base64.StdEncoding.EncodeToString(otherKey)
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		keys    []string
+		wantErr string
+	}{
+		{
+			name:  "trusted key",
+			files: fstest.MapFS{"index.yaml": {Data: manifest}, "index.yaml.sig": {Data: []byte(signature)}},
+			keys:  []string{trusted},
+		},
+		{
+			name:  "prefixed key",
+			files: fstest.MapFS{"index.yaml": {Data: manifest}, "index.yaml.sig": {Data: []byte(signature + "\n")}},
+			keys:  []string{KeyPrefix + trusted},
+		},
+		{
+			name:  "any trusted key",
+			files: fstest.MapFS{"index.yaml": {Data: manifest}, "index.yaml.sig": {Data: []byte(signature)}},
+			keys:  []string{"not a key", untrusted, trusted},
+		},
+		{
+			name:    "untrusted key",
+			files:   fstest.MapFS{"index.yaml": {Data: manifest}, "index.yaml.sig": {Data: []byte(signature)}},
+			keys:    []string{untrusted},
+			wantErr: "does not match any trusted key",
+		},
+		{
+			name:    "no trusted keys",
+			files:   fstest.MapFS{"index.yaml": {Data: manifest}, "index.yaml.sig": {Data: []byte(signature)}},
+			wantErr: "does not match any trusted key",
+		},
+		{
+			name:    "tampered manifest",
+			files:   fstest.MapFS{"index.yaml": {Data: []byte("version: 9.9.9\n")}, "index.yaml.sig": {Data: []byte(signature)}},
+			keys:    []string{trusted},
+			wantErr: "does not match any trusted key",
+		},
+		{
+			name:    "missing signature",
+			files:   fstest.MapFS{"index.yaml": {Data: manifest}},
+			keys:    []string{trusted},
+			wantErr: "is not signed",
+		},
+		{
+			name:    "malformed signature",
+			files:   fstest.MapFS{"index.yaml": {Data: manifest}, "index.yaml.sig": {Data: []byte("!!!")}},
+			keys:    []string{trusted},
+			wantErr: "invalid signature",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(tt.files, "index.yaml", tt.keys)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("VerifySignature() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VerifySignature() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	agent := []byte("---\nname: reviewer\n---\nReview code.\n")
+	script := []byte("print('hi')\n")
+	lintHook := "name: lint\nfiles:\n  - source: lint/run.sh\n    dest: .claude/hooks/run.sh\n  - sound: notification.aiff\n    dest: .claude/sounds/done.aiff\n"
+	files := fstest.MapFS{
+		"agents/reviewer.md": {Data: agent},
+		"hooks/notify.yaml":  {Data: []byte("name: notify\n")},
+		"hooks/notify.py":    {Data: script},
+		"hooks/lint.yaml":    {Data: []byte(lintHook)},
+		"hooks/lint/run.sh":  {Data: script},
+	}
+	reviewer := &assets.Template{Name: "reviewer", Type: "agent", Path: "agents/reviewer.md", FS: files}
+	notify := &assets.Template{Name: "notify", Type: "hook", Path: "hooks/notify.yaml", FS: files}
+	lint := &assets.Template{Name: "lint", Type: "hook", Path: "hooks/lint.yaml", FS: files}
+
+	tests := []struct {
+		name      string
+		template  *assets.Template
+		checksums map[string]string
+		want      []string
+	}{
+		{
+			name:      "match",
+			template:  reviewer,
+			checksums: map[string]string{"agents/reviewer.md": config.HashBytes(agent)},
+		},
+		{
+			name:      "bare hex digest",
+			template:  reviewer,
+			checksums: map[string]string{"agents/reviewer.md": strings.ToUpper(strings.TrimPrefix(config.HashBytes(agent), "sha256:"))},
+		},
+		{
+			name:      "mismatch",
+			template:  reviewer,
+			checksums: map[string]string{"agents/reviewer.md": config.HashBytes([]byte("something else"))},
+			want:      []string{"agents/reviewer.md does not match its checksum"},
+		},
+		{
+			name:     "missing checksum",
+			template: reviewer,
+			want:     []string{"agents/reviewer.md has no checksum in the manifest"},
+		},
+		{
+			name:     "hook script covered",
+			template: notify,
+			checksums: map[string]string{
+				"hooks/notify.yaml": config.HashBytes([]byte("name: notify\n")),
+				"hooks/notify.py":   config.HashBytes([]byte("print('tampered')\n")),
+			},
+			want: []string{"hooks/notify.py does not match its checksum"},
+		},
+		{
+			name:     "hook files covered",
+			template: lint,
+			checksums: map[string]string{
+				"hooks/lint.yaml":   config.HashBytes([]byte(lintHook)),
+				"hooks/lint/run.sh": config.HashBytes(script),
+			},
+		},
+		{
+			name:      "hook file without checksum",
+			template:  lint,
+			checksums: map[string]string{"hooks/lint.yaml": config.HashBytes([]byte(lintHook))},
+			want:      []string{"hooks/lint/run.sh has no checksum in the manifest"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyChecksums(tt.template, tt.checksums)
+			if err != nil {
+				t.Fatalf("VerifyChecksums() error = %v", err)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("VerifyChecksums() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(publicKey)
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "bare", key: encoded},
+		{name: "prefixed", key: KeyPrefix + encoded},
+		{name: "surrounding space", key: " " + encoded + "\n"},
+		{name: "not base64", key: "not a key", wantErr: true},
+		{name: "wrong length", key: base64.StdEncoding.EncodeToString([]byte("short")), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePublicKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePublicKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(publicKey) {
+				t.Errorf("ParsePublicKey() = %x, want %x", got, publicKey)
+			}
+		})
+	}
+}
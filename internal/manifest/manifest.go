@@ -0,0 +1,123 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/zxj777/claude-helper/internal/assets"
+	"github.com/zxj777/claude-helper/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// IndexFile is the name of the per-source manifest index
+const IndexFile = "index.yaml"
+
+// Load returns the manifest for a template, or nil if it has none.
+// A <name>.manifest.yaml next to the template takes precedence over the
+// entry for its type and name in the template directory's index.yaml.
+func Load(template *assets.Template) (*types.ComponentManifest, error) {
+	manifestPath := template.ManifestPath()
+	if data, err := fs.ReadFile(template.FS, manifestPath); err == nil {
+		var m types.ComponentManifest
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest %s: %w", manifestPath, err)
+		}
+		return &m, nil
+	}
+
+	index, err := LoadIndex(template.FS)
+	if err != nil {
+		return nil, err
+	}
+	if index == nil {
+		return nil, nil
+	}
+	typeDir, _, err := assets.TemplateLocation(template.Type)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := index.Lookup(typeDir, template.Name); ok {
+		return &m, nil
+	}
+	return nil, nil
+}
+
+// LoadIndex reads the index.yaml of a template directory, returning nil if there is none
+func LoadIndex(fsys fs.FS) (types.TemplateIndex, error) {
+	data, err := fs.ReadFile(fsys, IndexFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IndexFile, err)
+	}
+
+	var index types.TemplateIndex
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", IndexFile, err)
+	}
+	return index, nil
+}
+
+// Problem is a compatibility issue found in a manifest
+type Problem struct {
+	Message string
+	Fatal   bool // Fatal problems block installation; others are warnings
+}
+
+// CheckCompatibility validates a manifest against the running cchp version,
+// the current platform and the binaries available on PATH
+func CheckCompatibility(m *types.ComponentManifest, cchpVersion string) []Problem {
+	var problems []Problem
+
+	if m.Version != "" {
+		if _, err := ParseVersion(m.Version); err != nil {
+			problems = append(problems, Problem{Message: err.Error()})
+		}
+	}
+
+	if m.MinCchpVersion != "" {
+		cmp, err := CompareVersions(cchpVersion, m.MinCchpVersion)
+		switch {
+		case err != nil:
+			problems = append(problems, Problem{Message: fmt.Sprintf("invalid min_cchp_version: %v", err)})
+		case cmp < 0:
+			problems = append(problems, Problem{
+				Message: fmt.Sprintf("requires cchp %s or newer (running %s)", m.MinCchpVersion, cchpVersion),
+				Fatal:   true,
+			})
+		}
+	}
+
+	if len(m.Platforms) > 0 && !supportsPlatform(m.Platforms, runtime.GOOS) {
+		problems = append(problems, Problem{
+			Message: fmt.Sprintf("supports %s only (running %s)", strings.Join(m.Platforms, ", "), runtime.GOOS),
+			Fatal:   true,
+		})
+	}
+
+	for _, binary := range m.Binaries {
+		if _, err := exec.LookPath(binary); err != nil {
+			problems = append(problems, Problem{Message: fmt.Sprintf("required binary '%s' not found in PATH", binary)})
+		}
+	}
+
+	return problems
+}
+
+func supportsPlatform(platforms []string, goos string) bool {
+	for _, platform := range platforms {
+		platform = strings.ToLower(strings.TrimSpace(platform))
+		if platform == "macos" {
+			platform = "darwin"
+		}
+		if platform == goos || platform == "all" || platform == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package manifest
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{name: "equal", a: "1.2.3", b: "1.2.3", want: 0},
+		{name: "leading v", a: "v1.2.3", b: "1.2.3", want: 0},
+		{name: "build metadata ignored", a: "1.2.3+abc", b: "1.2.3+def", want: 0},
+		{name: "major", a: "2.0.0", b: "1.9.9", want: 1},
+		{name: "minor", a: "1.2.0", b: "1.10.0", want: -1},
+		{name: "patch", a: "1.2.10", b: "1.2.9", want: 1},
+		{name: "release above prerelease", a: "1.0.0", b: "1.0.0-rc.1", want: 1},
+		{name: "prerelease below release", a: "1.0.0-alpha", b: "1.0.0", want: -1},
+		{name: "numeric prerelease identifiers", a: "1.0.0-rc.2", b: "1.0.0-rc.10", want: -1},
+		{name: "numeric before alphanumeric", a: "1.0.0-1", b: "1.0.0-alpha", want: -1},
+		{name: "alphanumeric prerelease identifiers", a: "1.0.0-beta", b: "1.0.0-alpha", want: 1},
+		{name: "longer prerelease wins", a: "1.0.0-alpha.1", b: "1.0.0-alpha", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompareVersions(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("CompareVersions() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVersionInvalid(t *testing.T) {
+	for _, version := range []string{"", "1", "1.2", "1.2.x", "1.2.3.4", "a.b.c", "1.2.-3", "1.2.3-", "1.2.3-rc..1"} {
+		t.Run(version, func(t *testing.T) {
+			if _, err := ParseVersion(version); err == nil {
+				t.Errorf("ParseVersion(%q) error = nil, want an error", version)
+			}
+		})
+	}
+}
@@ -45,7 +45,7 @@ echo "   - install.ps1 (GitHub URL hardcoded: /main/install.ps1)"
 
 # Remove temporary/build artifacts
 echo "🗑️  Removing temporary files..."
-rm -f claude-helper  # Binary that should be in bin/
+rm -f cchp  # Binary that should be in bin/
 rm -f test_assets.go test_escape_logic.py test_settings.json test_templates.go
 
 # Update script references in moved scripts
@@ -84,5 +84,5 @@ echo "   ✅ GitHub install URLs preserved (/main/install.sh, /main/install.ps1)
 echo "   ✅ Release scripts updated to reference ../build.sh"
 echo ""
 echo "🎯 Cleaned up temporary files:"
-echo "   - claude-helper (binary build artifact)"
+echo "   - cchp (binary build artifact)"
 echo "   - test_*.go, test_*.py, test_*.json (temporary test files)"
\ No newline at end of file
@@ -35,9 +35,27 @@ func (a *Agent) ToMarkdown() string {
 
 // AgentFrontmatter represents the YAML frontmatter in agent files
 type AgentFrontmatter struct {
-	Name        string `yaml:"name"`
-	Description string `yaml:"description"`
-	Tools       string `yaml:"tools,omitempty"` // Tools as comma-separated string
+	Name        string          `yaml:"name"`
+	Description string          `yaml:"description"`
+	Tools       string          `yaml:"tools,omitempty"`     // Tools as comma-separated string
+	Extends     string          `yaml:"extends,omitempty"`   // Parent agent template to inherit from
+	Variables   []AgentVariable `yaml:"variables,omitempty"` // Template variables resolved at install time
+}
+
+// AgentVariable declares a template variable in an agent's frontmatter
+type AgentVariable struct {
+	Name    string `yaml:"name"`
+	Prompt  string `yaml:"prompt,omitempty"`  // Question shown when asking interactively
+	Default string `yaml:"default,omitempty"` // Value used when nothing else is provided
+}
+
+// SplitFrontmatter splits markdown content into its YAML frontmatter and body
+func SplitFrontmatter(content string) (frontmatter string, body string, err error) {
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 || strings.TrimSpace(parts[0]) != "" {
+		return "", "", fmt.Errorf("invalid agent format: missing frontmatter")
+	}
+	return strings.TrimSpace(parts[1]), strings.TrimLeft(parts[2], "\r\n"), nil
 }
 
 // ParseAgentFromMarkdown parses agent configuration from markdown content
@@ -0,0 +1,15 @@
+package types
+
+// Bundle installs a set of components together, e.g. a team's standard setup
+type Bundle struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Components  []BundleComponent `yaml:"components"`
+}
+
+// BundleComponent is a single component installed by a bundle
+type BundleComponent struct {
+	Name   string            `yaml:"name"`             // Template reference, optionally qualified with a source
+	Params map[string]string `yaml:"params,omitempty"` // Agent template variable values
+	Scope  string            `yaml:"scope,omitempty"`  // "project" (default) or "user"; user scope is supported for agents only
+}
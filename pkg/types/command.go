@@ -0,0 +1,136 @@
+package types
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CommandNamespaceSeparator joins the subdirectories of a namespaced command
+// into its component name: commands/frontend/component.md is frontend:component
+const CommandNamespaceSeparator = ":"
+
+// Command represents a Claude Code custom slash command (.claude/commands/*.md)
+type Command struct {
+	Name        string
+	Frontmatter CommandFrontmatter
+	Body        string
+}
+
+// CommandFrontmatter represents the optional YAML frontmatter in command files
+type CommandFrontmatter struct {
+	Description            string   `yaml:"description,omitempty"`
+	ArgumentHint           string   `yaml:"argument-hint,omitempty"`
+	AllowedTools           ToolList `yaml:"allowed-tools,omitempty"`
+	Model                  string   `yaml:"model,omitempty"`
+	DisableModelInvocation bool     `yaml:"disable-model-invocation,omitempty"`
+}
+
+// CommandFrontmatterKeys are the frontmatter keys Claude Code understands
+var CommandFrontmatterKeys = []string{"description", "argument-hint", "allowed-tools", "model", "disable-model-invocation"}
+
+// ToolList is a list of tools, written in YAML either as a list or as a
+// comma-separated string such as "Bash(git add:*), Read"
+type ToolList []string
+
+// UnmarshalYAML accepts a comma-separated string or a list of tools
+func (t *ToolList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		*t = splitTools(value.Value)
+		return nil
+	case yaml.SequenceNode:
+		var tools []string
+		if err := value.Decode(&tools); err != nil {
+			return err
+		}
+		*t = tools
+		return nil
+	default:
+		return fmt.Errorf("line %d: allowed-tools must be a string or a list", value.Line)
+	}
+}
+
+// MarshalYAML writes the tools as a comma-separated string, as Claude Code's docs do
+func (t ToolList) MarshalYAML() (interface{}, error) {
+	return strings.Join(t, ", "), nil
+}
+
+// splitTools splits a comma-separated tool list, keeping commas inside a tool's parentheses
+func splitTools(value string) []string {
+	var tools []string
+	depth, start := 0, 0
+	for i, char := range value {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				tools = appendTool(tools, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return appendTool(tools, value[start:])
+}
+
+func appendTool(tools []string, tool string) []string {
+	if tool = strings.TrimSpace(tool); tool != "" {
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+// ParseCommand parses a command file. Unlike agents, the frontmatter is optional.
+func ParseCommand(name, content string) (*Command, error) {
+	command := &Command{Name: name, Body: content}
+	if !strings.HasPrefix(content, "---") {
+		return command, nil
+	}
+
+	frontmatter, body, err := SplitFrontmatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command format: frontmatter is not closed with ---")
+	}
+	if err := yaml.Unmarshal([]byte(frontmatter), &command.Frontmatter); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	command.Body = body
+	return command, nil
+}
+
+// ToMarkdown converts the Command to Claude Code's command file format
+func (c *Command) ToMarkdown() (string, error) {
+	data, err := yaml.Marshal(c.Frontmatter)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	if strings.TrimSpace(string(data)) == "{}" {
+		return c.Body, nil
+	}
+	return "---\n" + string(data) + "---\n\n" + c.Body, nil
+}
+
+// CommandFilePath returns the slash-separated path of a command's file relative
+// to a commands directory, e.g. frontend/component.md for frontend:component
+func CommandFilePath(name string) string {
+	return strings.ReplaceAll(name, CommandNamespaceSeparator, "/") + ".md"
+}
+
+// CommandNameFromPath is the inverse of CommandFilePath
+func CommandNameFromPath(file string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(file, path.Ext(file)), "/", CommandNamespaceSeparator)
+}
+
+// CommandInvocation returns how the command is typed in Claude Code, e.g.
+// /component for frontend:component
+func CommandInvocation(name string) string {
+	parts := strings.Split(name, CommandNamespaceSeparator)
+	return "/" + parts[len(parts)-1]
+}
@@ -20,6 +20,29 @@ const (
 	SessionEnd       HookEvent = "SessionEnd"
 )
 
+// AllHookEvents lists every hook event supported by Claude Code
+var AllHookEvents = []HookEvent{
+	PreToolUse,
+	PostToolUse,
+	UserPromptSubmit,
+	Notification,
+	Stop,
+	SubagentStop,
+	PreCompact,
+	SessionStart,
+	SessionEnd,
+}
+
+// IsValid reports whether the event is a known Claude Code hook event
+func (e HookEvent) IsValid() bool {
+	for _, event := range AllHookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
 // Hook represents a Claude Code hook configuration
 type Hook struct {
 	Name        string    `json:"name" yaml:"name"`
@@ -30,8 +53,61 @@ type Hook struct {
 	Command     string    `json:"command" yaml:"command"`
 	Timeout     int       `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 	Enabled     bool      `json:"enabled" yaml:"enabled"`
+
+	Files     []HookFile     `json:"files,omitempty" yaml:"files,omitempty"`         // Extra files copied into the project
+	Config    []HookConfig   `json:"config,omitempty" yaml:"config,omitempty"`       // JSON config files created with defaults
+	Questions []HookQuestion `json:"questions,omitempty" yaml:"questions,omitempty"` // Asked before a config file is first written
+	Cleanup   []HookCleanup  `json:"cleanup,omitempty" yaml:"cleanup,omitempty"`     // Paths removed along with the hook
 }
 
+// HookFile copies a file from the hook's template directory, or a built-in sound, into the project
+type HookFile struct {
+	Source       string `json:"source,omitempty" yaml:"source,omitempty"`               // Path relative to the hook template's directory
+	Sound        string `json:"sound,omitempty" yaml:"sound,omitempty"`                 // Name of a built-in sound, instead of source
+	Dest         string `json:"dest" yaml:"dest"`                                       // Path relative to the project root
+	Mode         string `json:"mode,omitempty" yaml:"mode,omitempty"`                   // Octal file mode, default 0644
+	KeepExisting bool   `json:"keep_existing,omitempty" yaml:"keep_existing,omitempty"` // Don't overwrite a file that already exists
+}
+
+// HookConfig is a JSON config file written on install unless it already exists
+type HookConfig struct {
+	Path     string                 `json:"path" yaml:"path"` // Path relative to the project root
+	Defaults map[string]interface{} `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+}
+
+// Hook question types
+const (
+	QuestionString   = "string"
+	QuestionBool     = "bool"
+	QuestionChoice   = "choice"
+	QuestionMappings = "mappings"
+)
+
+// HookQuestion asks the user for a config value. Answers are stored under Key,
+// a dot-separated path into the config file; choices set their own keys instead.
+type HookQuestion struct {
+	Config      string       `json:"config,omitempty" yaml:"config,omitempty"` // Config path; defaults to the hook's first config
+	Key         string       `json:"key,omitempty" yaml:"key,omitempty"`
+	Type        string       `json:"type,omitempty" yaml:"type,omitempty"` // string (default), bool, choice or mappings
+	Prompt      string       `json:"prompt" yaml:"prompt"`
+	Help        string       `json:"help,omitempty" yaml:"help,omitempty"`                 // Printed before the prompt
+	Default     string       `json:"default,omitempty" yaml:"default,omitempty"`           // For choice, the 1-based choice number
+	Choices     []HookChoice `json:"choices,omitempty" yaml:"choices,omitempty"`           // For choice questions
+	ValuePrompt string       `json:"value_prompt,omitempty" yaml:"value_prompt,omitempty"` // For mappings, asked for each entry's value
+	Pattern     string       `json:"pattern,omitempty" yaml:"pattern,omitempty"`           // For mappings, regexp entry keys must match
+}
+
+// HookChoice is one answer to a choice question and the config values it sets
+type HookChoice struct {
+	Label string                 `json:"label" yaml:"label"`
+	Set   map[string]interface{} `json:"set" yaml:"set"` // Dot-separated keys to values
+}
+
+// HookCleanup is a file or directory removed when the hook is removed
+type HookCleanup struct {
+	Path    string `json:"path" yaml:"path"`                           // Path relative to the project root
+	Confirm string `json:"confirm,omitempty" yaml:"confirm,omitempty"` // Ask this question before removing
+}
 // ToClaudeHookEntry converts the Hook to a single hook entry format
 func (h *Hook) ToClaudeHookEntry() map[string]interface{} {
 	hook := map[string]interface{}{
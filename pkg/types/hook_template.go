@@ -0,0 +1,91 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Hook template encodings
+const (
+	HookFormatYAML = "yaml"
+	HookFormatJSON = "json"
+)
+
+// ErrLegacyHook is returned when parsing a hook template in the legacy JSON format
+var ErrLegacyHook = errors.New("legacy hook template with an inline script; run 'cchp template migrate' to convert it")
+
+// LegacyHook is the old JSON hook format, whose command named an inline script
+type LegacyHook struct {
+	Hook   `yaml:",inline"`
+	Script string `json:"script,omitempty" yaml:"script,omitempty"`
+}
+
+// HookFormatForPath returns the encoding of a hook template file from its extension
+func HookFormatForPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return HookFormatJSON
+	}
+	return HookFormatYAML
+}
+
+// ParseLegacyHook decodes a hook template in either encoding, keeping a legacy inline script
+func ParseLegacyHook(data []byte, format string) (*LegacyHook, error) {
+	var hook LegacyHook
+	switch format {
+	case HookFormatJSON:
+		if err := json.Unmarshal(data, &hook); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	case HookFormatYAML:
+		if err := yaml.Unmarshal(data, &hook); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown hook template format: %s", format)
+	}
+	return &hook, nil
+}
+
+// ParseHook decodes a hook template in the canonical schema from YAML or JSON
+func ParseHook(data []byte, format string) (*Hook, error) {
+	hook, err := ParseLegacyHook(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if hook.Script != "" {
+		return nil, ErrLegacyHook
+	}
+	return &hook.Hook, nil
+}
+
+// EncodeHook encodes a hook template in the canonical schema as YAML or JSON
+func EncodeHook(hook *Hook, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case HookFormatJSON:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(hook); err != nil {
+			return nil, fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	case HookFormatYAML:
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(hook); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown hook template format: %s", format)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,58 @@
+package types
+
+import "strings"
+
+// LockfileVersion is the current format version of .claude/cchp.lock.
+// Version 1 keyed components by bare name; version 2 keys them with LockKey.
+const LockfileVersion = 2
+
+// Lockfile records every component cchp installed into a project's .claude
+// directory so the exact setup can be reproduced with 'cchp sync'. Components
+// installed for the user are recorded in the same format in a user lockfile.
+type Lockfile struct {
+	LockfileVersion int                        `json:"lockfile_version"`
+	Components      map[string]LockedComponent `json:"components"`
+	Bundles         map[string]LockedBundle    `json:"bundles,omitempty"`
+}
+
+// LockedBundle records which components a bundle installed, so removing the
+// bundle leaves alone components that were installed separately
+type LockedBundle struct {
+	Template   string   `json:"template"`
+	Components []string `json:"components"` // Lock keys, see LockKey
+}
+
+// LockKey returns the key a component is recorded under, <type>/<name>, with
+// @<scope> appended outside the project so an agent and a hook, or a project
+// and a local preset, with the same name get separate entries
+func LockKey(componentType, name, scope string) string {
+	key := componentType + "/" + name
+	if scope != "" && scope != "project" {
+		key += "@" + scope
+	}
+	return key
+}
+
+// SplitLockKey returns the type, name and scope of a key made by LockKey.
+// The scope is empty for the project.
+func SplitLockKey(key string) (componentType, name, scope string) {
+	componentType, name, _ = strings.Cut(key, "/")
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		name, scope = name[:i], name[i+1:]
+	}
+	return componentType, name, scope
+}
+
+// LockedComponent records how a single component was installed
+type LockedComponent struct {
+	Type     string            `json:"type"`              // "agent", "hook", "command", "mcp" or "permission"
+	Template string            `json:"template"`          // Template reference used to install, e.g. team/security-check
+	Source   string            `json:"source"`            // Template origin: project, user, built-in or a source name
+	Scope    string            `json:"scope,omitempty"`   // "user" for components installed for the user, "local" for permission presets in settings.local.json; empty for the project
+	Version  string            `json:"version,omitempty"` // Manifest version at install time
+	Params   map[string]string `json:"params,omitempty"`  // Template variable values, or the answers to a hook's questions
+	Files    map[string]string `json:"files,omitempty"`   // Installed file (relative to the project root, or ~/ for user scope) -> sha256:<hex>
+
+	TemplateHash string       `json:"template_hash,omitempty"` // Checksum of the template files installed from; sync refuses to install from a changed template
+	Added        *Permissions `json:"added,omitempty"`         // Rules and defaultMode a permission preset added to its settings file; removing it takes out only these
+}
@@ -0,0 +1,34 @@
+package types
+
+import "testing"
+
+func TestLockKey(t *testing.T) {
+	tests := []struct {
+		name          string
+		componentType string
+		component     string
+		scope         string
+		want          string
+		wantScope     string
+	}{
+		{name: "project", componentType: "agent", component: "reviewer", want: "agent/reviewer"},
+		{name: "explicit project", componentType: "hook", component: "reviewer", scope: "project", want: "hook/reviewer"},
+		{name: "local", componentType: "permission", component: "safe", scope: "local", want: "permission/safe@local", wantScope: "local"},
+		{name: "user", componentType: "agent", component: "reviewer", scope: "user", want: "agent/reviewer@user", wantScope: "user"},
+		{name: "namespaced command", componentType: "command", component: "git:commit", want: "command/git:commit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := LockKey(tt.componentType, tt.component, tt.scope)
+			if key != tt.want {
+				t.Fatalf("LockKey() = %q, want %q", key, tt.want)
+			}
+			componentType, component, scope := SplitLockKey(key)
+			if componentType != tt.componentType || component != tt.component || scope != tt.wantScope {
+				t.Errorf("SplitLockKey(%q) = %q, %q, %q, want %q, %q, %q",
+					key, componentType, component, scope, tt.componentType, tt.component, tt.wantScope)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package types
+
+// ComponentManifest describes a template's version and compatibility requirements.
+// It can live next to the template as <name>.manifest.yaml or in an index.yaml
+// at the root of a template directory.
+type ComponentManifest struct {
+	Version        string            `yaml:"version" json:"version"` // Semantic version of the component
+	Author         string            `yaml:"author,omitempty" json:"author,omitempty"`
+	MinCchpVersion string            `yaml:"min_cchp_version,omitempty" json:"min_cchp_version,omitempty"` // Oldest cchp able to install it
+	Platforms      []string          `yaml:"platforms,omitempty" json:"platforms,omitempty"`               // darwin, linux, windows; empty means all
+	Binaries       []string          `yaml:"binaries,omitempty" json:"binaries,omitempty"`                 // Executables the component needs at runtime
+	Checksums      map[string]string `yaml:"checksums,omitempty" json:"checksums,omitempty"`               // File path relative to the template root -> sha256:<hex>
+	Changelog      string            `yaml:"changelog,omitempty" json:"changelog,omitempty"`
+	Requires       Requirements      `yaml:"requires,omitempty" json:"requires,omitempty"` // Components and shared files installed first
+}
+
+// Requirements lists what a component needs installed before it can work
+type Requirements struct {
+	Components []string `yaml:"components,omitempty" json:"components,omitempty"` // Template references, e.g. code-reviewer or team/linter
+	Runtime    []string `yaml:"runtime,omitempty" json:"runtime,omitempty"`       // Shared runtime files, e.g. run-python
+}
+
+// TemplateIndex is the per-source index.yaml. Manifests are grouped by the
+// directory of their template type (agents, hooks, commands, mcp, permissions),
+// so an agent and a hook may share a name without sharing a manifest.
+type TemplateIndex map[string]map[string]ComponentManifest
+
+// Lookup returns the manifest listed for a component under its type's directory
+func (i TemplateIndex) Lookup(typeDir, name string) (ComponentManifest, bool) {
+	m, ok := i[typeDir][name]
+	return m, ok
+}
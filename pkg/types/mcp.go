@@ -0,0 +1,173 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MCP server transports
+const (
+	MCPTransportStdio = "stdio"
+	MCPTransportSSE   = "sse"
+	MCPTransportHTTP  = "http"
+)
+
+// MCPServer is an MCP server template and its entry in .mcp.json or ~/.claude.json.
+// Values may refer to environment variables as ${VAR} or ${VAR:-default}, which
+// Claude Code expands when it starts the server, so secrets stay out of the file.
+type MCPServer struct {
+	Name        string            `json:"-" yaml:"name,omitempty"`
+	Description string            `json:"-" yaml:"description,omitempty"`
+	Type        string            `json:"type,omitempty" yaml:"type,omitempty"` // stdio (default), sse or http
+	Command     string            `json:"command,omitempty" yaml:"command,omitempty"`
+	Args        []string          `json:"args,omitempty" yaml:"args,omitempty"`
+	Env         map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	URL         string            `json:"url,omitempty" yaml:"url,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+// Transport returns the server's transport, stdio unless a URL says otherwise
+func (s *MCPServer) Transport() string {
+	if s.Type != "" {
+		return s.Type
+	}
+	if s.URL != "" && s.Command == "" {
+		return MCPTransportHTTP
+	}
+	return MCPTransportStdio
+}
+
+// Validate checks that the server has what its transport needs
+func (s *MCPServer) Validate() error {
+	switch s.Transport() {
+	case MCPTransportStdio:
+		if s.Command == "" {
+			return fmt.Errorf("stdio server needs a command")
+		}
+		if s.URL != "" {
+			return fmt.Errorf("stdio server can't have a url")
+		}
+	case MCPTransportSSE, MCPTransportHTTP:
+		if s.URL == "" {
+			return fmt.Errorf("%s server needs a url", s.Transport())
+		}
+		if s.Command != "" || len(s.Args) > 0 || len(s.Env) > 0 {
+			return fmt.Errorf("%s server can't have a command, args or env", s.Transport())
+		}
+	default:
+		return fmt.Errorf("invalid server type '%s' (use %s, %s or %s)", s.Type, MCPTransportStdio, MCPTransportSSE, MCPTransportHTTP)
+	}
+	return nil
+}
+
+// Target describes where the server runs, for listings
+func (s *MCPServer) Target() string {
+	if s.Transport() == MCPTransportStdio {
+		return strings.TrimSpace(s.Command + " " + strings.Join(s.Args, " "))
+	}
+	return s.URL
+}
+
+// mcpPlaceholderPattern matches ${VAR} and ${VAR:-default}
+var mcpPlaceholderPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// values returns every string in the server that placeholders may appear in
+func (s *MCPServer) values() []string {
+	values := []string{s.Command, s.URL}
+	values = append(values, s.Args...)
+	for _, value := range s.Env {
+		values = append(values, value)
+	}
+	for _, value := range s.Headers {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Placeholders returns the environment variables the server refers to, sorted.
+// Variables with a default are only included when required is false.
+func (s *MCPServer) Placeholders(required bool) []string {
+	seen := make(map[string]bool)
+	for _, value := range s.values() {
+		for _, match := range mcpPlaceholderPattern.FindAllStringSubmatch(value, -1) {
+			if required && strings.Contains(match[0], ":-") {
+				continue
+			}
+			seen[match[1]] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Expand returns a copy of the server with its placeholders replaced the way
+// Claude Code does, and the variables that were unset without a default
+func (s *MCPServer) Expand(lookup func(string) (string, bool)) (*MCPServer, []string) {
+	missing := make(map[string]bool)
+	expand := func(value string) string {
+		return mcpPlaceholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+			match := mcpPlaceholderPattern.FindStringSubmatch(placeholder)
+			if value, ok := lookup(match[1]); ok && value != "" {
+				return value
+			}
+			if strings.Contains(placeholder, ":-") {
+				return match[2]
+			}
+			missing[match[1]] = true
+			return ""
+		})
+	}
+	expandMap := func(values map[string]string) map[string]string {
+		if values == nil {
+			return nil
+		}
+		expanded := make(map[string]string, len(values))
+		for key, value := range values {
+			expanded[key] = expand(value)
+		}
+		return expanded
+	}
+
+	expanded := *s
+	expanded.Command = expand(s.Command)
+	expanded.URL = expand(s.URL)
+	expanded.Args = nil
+	for _, arg := range s.Args {
+		expanded.Args = append(expanded.Args, expand(arg))
+	}
+	expanded.Env = expandMap(s.Env)
+	expanded.Headers = expandMap(s.Headers)
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &expanded, names
+}
+
+// secretNamePattern matches env and header names that usually hold credentials
+var secretNamePattern = regexp.MustCompile(`(?i)(token|secret|password|passwd|api[-_]?key|auth|credential|private[-_]?key)`)
+
+// LiteralSecrets returns the env and header names that look like credentials
+// but hold a literal value instead of a ${VAR} placeholder
+func (s *MCPServer) LiteralSecrets() []string {
+	var names []string
+	check := func(values map[string]string) {
+		for name, value := range values {
+			if value != "" && secretNamePattern.MatchString(name) && !mcpPlaceholderPattern.MatchString(value) {
+				names = append(names, name)
+			}
+		}
+	}
+	check(s.Env)
+	check(s.Headers)
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,216 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Permission rule behaviors, in the order Claude Code applies them: a deny rule
+// wins over an ask rule, which wins over an allow rule, whichever file they are in
+const (
+	PermissionDeny  = "deny"
+	PermissionAsk   = "ask"
+	PermissionAllow = "allow"
+)
+
+// PermissionBehaviors lists the behaviors in precedence order
+var PermissionBehaviors = []string{PermissionDeny, PermissionAsk, PermissionAllow}
+
+// PermissionModes are the valid values of permissions.defaultMode
+var PermissionModes = []string{"default", "acceptEdits", "plan", "bypassPermissions"}
+
+// KnownTools are Claude Code's built-in tools, which permission rules and
+// allowed-tools may name besides mcp__ tools
+var KnownTools = map[string]bool{
+	"Bash": true, "BashOutput": true, "Edit": true, "ExitPlanMode": true, "Glob": true,
+	"Grep": true, "KillShell": true, "LS": true, "MultiEdit": true, "NotebookEdit": true,
+	"NotebookRead": true, "Read": true, "SlashCommand": true, "Skill": true, "Task": true,
+	"TodoWrite": true, "WebFetch": true, "WebSearch": true, "Write": true,
+}
+
+// Permissions represents the permissions section of a Claude Code settings file
+type Permissions struct {
+	Allow       []string `json:"allow,omitempty"`
+	Deny        []string `json:"deny,omitempty"`
+	Ask         []string `json:"ask,omitempty"`
+	DefaultMode string   `json:"defaultMode,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Keys like additionalDirectories, written back unchanged
+}
+
+// Rules returns the rule list of a behavior, for reading or changing it
+func (p *Permissions) Rules(behavior string) *[]string {
+	switch behavior {
+	case PermissionDeny:
+		return &p.Deny
+	case PermissionAsk:
+		return &p.Ask
+	default:
+		return &p.Allow
+	}
+}
+
+// IsEmpty reports whether there is nothing to write
+func (p *Permissions) IsEmpty() bool {
+	return len(p.Allow) == 0 && len(p.Deny) == 0 && len(p.Ask) == 0 && p.DefaultMode == "" && len(p.Extra) == 0
+}
+
+// UnmarshalJSON keeps the keys cchp doesn't manage in Extra
+func (p *Permissions) UnmarshalJSON(data []byte) error {
+	type plain Permissions
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &p.Extra); err != nil {
+		return err
+	}
+	for _, key := range []string{"allow", "deny", "ask", "defaultMode"} {
+		delete(p.Extra, key)
+	}
+	return nil
+}
+
+// MarshalJSON writes the managed keys together with Extra
+func (p Permissions) MarshalJSON() ([]byte, error) {
+	object := make(map[string]interface{}, len(p.Extra)+4)
+	for key, value := range p.Extra {
+		object[key] = value
+	}
+	if len(p.Allow) > 0 {
+		object["allow"] = p.Allow
+	}
+	if len(p.Deny) > 0 {
+		object["deny"] = p.Deny
+	}
+	if len(p.Ask) > 0 {
+		object["ask"] = p.Ask
+	}
+	if p.DefaultMode != "" {
+		object["defaultMode"] = p.DefaultMode
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(object); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+// PermissionPreset is a permissions template installed into a settings file
+type PermissionPreset struct {
+	Name        string   `yaml:"name,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	DefaultMode string   `yaml:"defaultMode,omitempty"`
+	Allow       []string `yaml:"allow,omitempty"`
+	Deny        []string `yaml:"deny,omitempty"`
+	Ask         []string `yaml:"ask,omitempty"`
+}
+
+// Rules returns the preset's rules of a behavior
+func (p *PermissionPreset) Rules(behavior string) []string {
+	switch behavior {
+	case PermissionDeny:
+		return p.Deny
+	case PermissionAsk:
+		return p.Ask
+	default:
+		return p.Allow
+	}
+}
+
+// PermissionRule is a parsed rule such as Bash(npm run test:*) or Read(./secrets/**)
+type PermissionRule struct {
+	Tool         string
+	Specifier    string
+	HasSpecifier bool
+}
+
+var toolNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// ParsePermissionRule parses and validates a permission rule
+func ParsePermissionRule(rule string) (PermissionRule, error) {
+	if strings.TrimSpace(rule) != rule || rule == "" {
+		return PermissionRule{}, fmt.Errorf("rule must not be empty or have surrounding spaces")
+	}
+
+	parsed := PermissionRule{Tool: rule}
+	if open := strings.Index(rule, "("); open >= 0 {
+		if !strings.HasSuffix(rule, ")") {
+			return PermissionRule{}, fmt.Errorf("'%s' opens a ( that doesn't close at the end of the rule", rule)
+		}
+		parsed = PermissionRule{Tool: rule[:open], Specifier: rule[open+1 : len(rule)-1], HasSpecifier: true}
+		if parsed.Specifier == "" {
+			return PermissionRule{}, fmt.Errorf("'%s' has an empty specifier; use %s to match every use of the tool", rule, parsed.Tool)
+		}
+	} else if strings.Contains(rule, ")") {
+		return PermissionRule{}, fmt.Errorf("'%s' has a ) without a (", rule)
+	}
+
+	if !toolNamePattern.MatchString(parsed.Tool) {
+		return PermissionRule{}, fmt.Errorf("'%s' is not a valid tool name", parsed.Tool)
+	}
+	if strings.HasPrefix(parsed.Tool, "mcp__") {
+		if parsed.HasSpecifier {
+			return PermissionRule{}, fmt.Errorf("MCP rules don't take a specifier; use mcp__server or mcp__server__tool")
+		}
+		return parsed, nil
+	}
+	if !KnownTools[parsed.Tool] {
+		return PermissionRule{}, fmt.Errorf("unknown tool '%s'", parsed.Tool)
+	}
+	if !parsed.HasSpecifier {
+		return parsed, nil
+	}
+
+	switch parsed.Tool {
+	case "Bash":
+		if i := strings.Index(parsed.Specifier, ":*"); i >= 0 && i != len(parsed.Specifier)-2 {
+			return PermissionRule{}, fmt.Errorf("'%s': the :* prefix wildcard only works at the end of a Bash rule", rule)
+		}
+	case "WebFetch":
+		if !strings.HasPrefix(parsed.Specifier, "domain:") || parsed.Specifier == "domain:" {
+			return PermissionRule{}, fmt.Errorf("'%s': WebFetch rules match a domain, e.g. WebFetch(domain:example.com)", rule)
+		}
+	default:
+		if strings.HasSuffix(parsed.Specifier, ":*") {
+			return PermissionRule{}, fmt.Errorf("'%s': the :* prefix wildcard only works for Bash; use a gitignore pattern such as ** for paths", rule)
+		}
+	}
+	return parsed, nil
+}
+
+// String formats the rule the way settings files write it
+func (r PermissionRule) String() string {
+	if !r.HasSpecifier {
+		return r.Tool
+	}
+	return r.Tool + "(" + r.Specifier + ")"
+}
+
+// Covers reports whether every use of the tool that other matches is also matched
+// by r: r has no specifier, the specifiers are equal, or r is a Bash prefix
+// rule (prefix:*) whose prefix starts other's command
+func (r PermissionRule) Covers(other PermissionRule) bool {
+	if r.Tool != other.Tool {
+		// mcp__server matches every tool of the server
+		return !r.HasSpecifier && strings.HasPrefix(r.Tool, "mcp__") && strings.HasPrefix(other.Tool, r.Tool+"__")
+	}
+	if !r.HasSpecifier {
+		return true
+	}
+	if !other.HasSpecifier {
+		return false
+	}
+	if r.Specifier == other.Specifier {
+		return true
+	}
+	if r.Tool == "Bash" && strings.HasSuffix(r.Specifier, ":*") {
+		return strings.HasPrefix(strings.TrimSuffix(other.Specifier, ":*"), strings.TrimSuffix(r.Specifier, ":*"))
+	}
+	return false
+}
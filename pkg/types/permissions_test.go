@@ -0,0 +1,97 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePermissionRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		want    PermissionRule
+		wantErr string
+	}{
+		{name: "tool only", rule: "Read", want: PermissionRule{Tool: "Read"}},
+		{name: "bash command", rule: "Bash(git status)", want: PermissionRule{Tool: "Bash", Specifier: "git status", HasSpecifier: true}},
+		{name: "bash prefix", rule: "Bash(npm run test:*)", want: PermissionRule{Tool: "Bash", Specifier: "npm run test:*", HasSpecifier: true}},
+		{name: "path pattern", rule: "Read(./secrets/**)", want: PermissionRule{Tool: "Read", Specifier: "./secrets/**", HasSpecifier: true}},
+		{name: "specifier with parentheses", rule: "Bash(echo (hi))", want: PermissionRule{Tool: "Bash", Specifier: "echo (hi)", HasSpecifier: true}},
+		{name: "webfetch domain", rule: "WebFetch(domain:example.com)", want: PermissionRule{Tool: "WebFetch", Specifier: "domain:example.com", HasSpecifier: true}},
+		{name: "mcp server", rule: "mcp__github", want: PermissionRule{Tool: "mcp__github"}},
+		{name: "mcp tool", rule: "mcp__github__create_issue", want: PermissionRule{Tool: "mcp__github__create_issue"}},
+		{name: "empty", rule: "", wantErr: "must not be empty"},
+		{name: "surrounding spaces", rule: " Read", wantErr: "surrounding spaces"},
+		{name: "unclosed", rule: "Bash(git status", wantErr: "doesn't close"},
+		{name: "close without open", rule: "Bash)", wantErr: "without a ("},
+		{name: "empty specifier", rule: "Bash()", wantErr: "empty specifier"},
+		{name: "invalid tool name", rule: "1Bash", wantErr: "not a valid tool name"},
+		{name: "unknown tool", rule: "Frobnicate", wantErr: "unknown tool"},
+		{name: "mcp with specifier", rule: "mcp__github(repo)", wantErr: "don't take a specifier"},
+		{name: "bash wildcard not at end", rule: "Bash(npm:* run)", wantErr: "only works at the end"},
+		{name: "webfetch without domain", rule: "WebFetch(example.com)", wantErr: "match a domain"},
+		{name: "webfetch empty domain", rule: "WebFetch(domain:)", wantErr: "match a domain"},
+		{name: "prefix wildcard on path", rule: "Read(src:*)", wantErr: "only works for Bash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePermissionRule(tt.rule)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParsePermissionRule(%q) error = %v, want it to contain %q", tt.rule, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePermissionRule(%q) error = %v", tt.rule, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePermissionRule(%q) = %+v, want %+v", tt.rule, got, tt.want)
+			}
+			if got.String() != tt.rule {
+				t.Errorf("String() = %q, want %q", got.String(), tt.rule)
+			}
+		})
+	}
+}
+
+func TestPermissionRuleCovers(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		other string
+		want  bool
+	}{
+		{name: "same rule", rule: "Bash(git status)", other: "Bash(git status)", want: true},
+		{name: "tool covers specifier", rule: "Bash", other: "Bash(git status)", want: true},
+		{name: "tool covers itself", rule: "Read", other: "Read", want: true},
+		{name: "specifier does not cover tool", rule: "Bash(git status)", other: "Bash", want: false},
+		{name: "different tools", rule: "Read", other: "Edit(src/**)", want: false},
+		{name: "different commands", rule: "Bash(git status)", other: "Bash(git diff)", want: false},
+		{name: "prefix covers command", rule: "Bash(npm run:*)", other: "Bash(npm run test)", want: true},
+		{name: "prefix covers narrower prefix", rule: "Bash(npm run:*)", other: "Bash(npm run test:*)", want: true},
+		{name: "narrower prefix does not cover wider", rule: "Bash(npm run test:*)", other: "Bash(npm run:*)", want: false},
+		{name: "prefix does not cover other command", rule: "Bash(npm run:*)", other: "Bash(npx tsc)", want: false},
+		{name: "prefix only for bash", rule: "Read(src/**)", other: "Read(src/main.go)", want: false},
+		{name: "mcp server covers its tools", rule: "mcp__github", other: "mcp__github__create_issue", want: true},
+		{name: "mcp server does not cover other servers", rule: "mcp__git", other: "mcp__github__create_issue", want: false},
+		{name: "mcp tool does not cover server", rule: "mcp__github__create_issue", other: "mcp__github", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParsePermissionRule(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			other, err := ParsePermissionRule(tt.other)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rule.Covers(other); got != tt.want {
+				t.Errorf("%s.Covers(%s) = %v, want %v", tt.rule, tt.other, got, tt.want)
+			}
+		})
+	}
+}
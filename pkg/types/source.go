@@ -0,0 +1,18 @@
+package types
+
+// Template source kinds
+const (
+	SourceTypeDir = "dir" // A local directory used in place
+	SourceTypeGit = "git" // A git repository cloned into the cache
+)
+
+// TemplateSource is an additional location that provides agent and hook templates
+type TemplateSource struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`          // "dir" or "git"
+	Location string `json:"location"`      // Directory path or git URL
+	Ref      string `json:"ref,omitempty"` // Branch or tag to check out for git sources
+	Priority int    `json:"priority"`      // Lower values are searched first
+
+	TrustedKeys []string `json:"trusted_keys,omitempty"` // ed25519 public keys; when set, manifests must be signed by one of them
+}
@@ -2,10 +2,27 @@ package types
 
 // TextExpanderConfig represents the configuration for text expander
 type TextExpanderConfig struct {
-	Mappings    map[string]string `json:"mappings"`
-	EscapeChar  string           `json:"escape_char,omitempty"`  // Default: "\"
+	Mappings        map[string]string `json:"mappings" yaml:"mappings"`
+	EscapeChar      string            `json:"escape_char,omitempty" yaml:"escape_char,omitempty"`           // Default: "\"
+	Commands        map[string]string `json:"commands,omitempty" yaml:"commands,omitempty"`                 // Commands snippets may run with {{cmd:name}}
+	Mode            string            `json:"mode,omitempty" yaml:"mode,omitempty"`                         // How the hook passes on the expansion, TextExpanderModeContext by default
+	Modes           map[string]string `json:"modes,omitempty" yaml:"modes,omitempty"`                       // Mode for individual markers, overriding Mode
+	ContextTemplate string            `json:"context_template,omitempty" yaml:"context_template,omitempty"` // Additional context; {{prompt}} is the expanded prompt
+	BlockTemplate   string            `json:"block_template,omitempty" yaml:"block_template,omitempty"`     // Reason shown when blocking; {{prompt}} is the expanded prompt
 }
 
+// Text expander modes
+const (
+	TextExpanderModeContext = "context" // Keep the prompt and give Claude the expansion as additional context
+	TextExpanderModeBlock   = "block"   // Block the prompt and show the expanded one, to edit and send instead
+)
+
+// Default text expander templates
+const (
+	DefaultTextExpanderContextTemplate = "用户的意思是: {{prompt}}"
+	DefaultTextExpanderBlockTemplate   = "{{prompt}}"
+)
+
 // NotificationConfig represents the configuration for task completion notifications
 type NotificationConfig struct {
 	NotificationTypes []string       `json:"notification_types"` // Types of notifications: "audio", "desktop"
@@ -199,7 +199,7 @@ generate_changelog() {
     echo "### Manual installation:"
     echo "1. Download the binary for your platform"
     echo "2. Move it to a directory in your PATH"
-    echo '3. Make it executable: `chmod +x claude-helper`'
+    echo '3. Make it executable: `chmod +x cchp`'
     echo ""
     echo "## Platform Support"
     echo "- Linux (amd64, arm64)"
@@ -185,7 +185,7 @@ curl -sSL https://raw.githubusercontent.com/$REPO/main/install.sh | bash
 ### Manual install:
 1. Download the binary for your platform
 2. Move it to your PATH
-3. Make executable: \`chmod +x claude-helper\`
+3. Make executable: \`chmod +x cchp\`
 
 ## Platforms
 - Linux (amd64, arm64)
@@ -40,7 +40,7 @@ curl -sSL https://raw.githubusercontent.com/$REPO/main/install.sh | bash
 ### Manual installation:
 1. Download the binary for your platform
 2. Move it to a directory in your PATH
-3. Make it executable: \`chmod +x claude-helper\`
+3. Make it executable: \`chmod +x cchp\`
 
 ## Platform Support
 - Linux (amd64, arm64)  
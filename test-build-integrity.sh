@@ -57,16 +57,16 @@ fi
 
 # Test 4: Go mod and source compilation
 log_test "Go source compilation"
-if go build -o /tmp/test-claude-helper ./cmd/claude-helper; then
+if go build -o /tmp/test-cchp ./cmd/cchp; then
     log_success "Go compilation successful"
-    rm -f /tmp/test-claude-helper
+    rm -f /tmp/test-cchp
 else
     log_error "Go compilation failed"
 fi
 
 # Test 5: Embedded assets can be loaded
 log_test "Embedded assets loading"
-if go run -ldflags "-X main.Version=test" ./cmd/claude-helper list >/dev/null 2>&1; then
+if go run -ldflags "-X main.Version=test" ./cmd/cchp list >/dev/null 2>&1; then
     log_success "Embedded assets loading correctly"
 else
     log_warning "Could not test embedded assets (command may require specific setup)"